@@ -0,0 +1,417 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command gotokenizers loads a tokenizer and uses it to encode, decode
+// and inspect text, reading one input per line from the given files or
+// from the standard input.
+//
+// Usage:
+//
+//	gotokenizers <command> [flags] [files...]
+//
+// The commands are:
+//
+//	encode   print IDs, tokens, offsets and word indices of each line
+//	decode   print the text obtained from each line of IDs
+//	count    print the number of tokens of each line
+//	inspect  print the output of each pipeline stage for each line
+//
+// The tokenizer is loaded from a tokenizer.json file (-tokenizer flag),
+// or from a vocabulary and an optional merges file (-vocab and -merges
+// flags). In the latter case, a byte-level BPE tokenizer (like GPT-2) is
+// built when the merges file is given, otherwise a WordPiece tokenizer
+// (like BERT) is built.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/nlpodyssey/gotokenizers"
+	"github.com/nlpodyssey/gotokenizers/decoders/wordpiecedecoder"
+	"github.com/nlpodyssey/gotokenizers/encodings"
+	"github.com/nlpodyssey/gotokenizers/models/bpemodel"
+	"github.com/nlpodyssey/gotokenizers/models/wordpiecemodel"
+	"github.com/nlpodyssey/gotokenizers/normalizers/bertnormalizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/bertpretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/bytelevelpretokenizer"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"github.com/nlpodyssey/gotokenizers/vocabulary"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// maxLineSize is the maximum size in bytes of a single input line.
+const maxLineSize = 64 * 1024 * 1024
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "gotokenizers: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+type options struct {
+	tokenizerFile string
+	vocabFile     string
+	mergesFile    string
+	lowercase     bool
+	format        string
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command: expected one of encode, decode, count, inspect")
+	}
+	command := args[0]
+
+	var opts options
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.StringVar(&opts.tokenizerFile, "tokenizer", "", "path to a tokenizer.json file")
+	fs.StringVar(&opts.vocabFile, "vocab", "", "path to a vocabulary file (vocab.json or vocab.txt)")
+	fs.StringVar(&opts.mergesFile, "merges", "", "path to a BPE merges file (merges.txt)")
+	fs.BoolVar(&opts.lowercase, "lowercase", true, "lowercase the input (WordPiece from -vocab only)")
+	fs.StringVar(&opts.format, "format", "json", `output format: "json" (JSON lines) or "table"`)
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if opts.format != "json" && opts.format != "table" {
+		return fmt.Errorf("unknown format %#v", opts.format)
+	}
+
+	var process func(tokenizer *gotokenizers.Tokenizer, line string, w io.Writer, format string) error
+	switch command {
+	case "encode":
+		process = encode
+	case "decode":
+		process = decode
+	case "count":
+		process = count
+	case "inspect":
+		process = inspect
+	default:
+		return fmt.Errorf("unknown command %#v", command)
+	}
+
+	tokenizer, err := loadTokenizer(opts)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	return forEachLine(fs.Args(), stdin, func(line string) error {
+		return process(tokenizer, line, out, opts.format)
+	})
+}
+
+func loadTokenizer(opts options) (*gotokenizers.Tokenizer, error) {
+	if opts.tokenizerFile != "" {
+		return gotokenizers.FromFile(opts.tokenizerFile)
+	}
+	if opts.vocabFile == "" {
+		return nil, fmt.Errorf("either -tokenizer or -vocab must be provided")
+	}
+
+	var vocab *vocabulary.Vocabulary
+	var err error
+	if strings.HasSuffix(opts.vocabFile, ".json") {
+		vocab, err = vocabulary.FromJSONFile(opts.vocabFile)
+	} else {
+		vocab, err = vocabulary.FromTxtFile(opts.vocabFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.mergesFile == "" {
+		return gotokenizers.NewTokenizer(
+			bertnormalizer.NewBertNormalizer(true, true, opts.lowercase, opts.lowercase),
+			bertpretokenizer.New(),
+			wordpiecemodel.New(vocab, "[UNK]", "##", 100),
+			wordpiecedecoder.NewDefault(),
+		), nil
+	}
+
+	merges, err := bpemodel.MergeMapFromFile(opts.mergesFile, vocab, 0)
+	if err != nil {
+		return nil, err
+	}
+	byteLevel := bytelevelpretokenizer.New(bytelevelpretokenizer.DefaultSplittingRegexp, false, false)
+	return gotokenizers.NewTokenizer(
+		nil,
+		byteLevel,
+		bpemodel.New(vocab, merges, bpemodel.DefaultCacheCapacity, 0, "", "", "", false),
+		byteLevel,
+	), nil
+}
+
+// forEachLine calls f for each line of the given files, or of stdin if no
+// files are given.
+func forEachLine(filenames []string, stdin io.Reader, f func(string) error) error {
+	if len(filenames) == 0 {
+		return scanLines(stdin, f)
+	}
+	for _, filename := range filenames {
+		file, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		err = scanLines(file, f)
+		if e := file.Close(); e != nil && err == nil {
+			err = e
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanLines(r io.Reader, f func(string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if err := f(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+type encodingOutput struct {
+	IDs     []int    `json:"ids"`
+	Tokens  []string `json:"tokens"`
+	Offsets [][2]int `json:"offsets"`
+	Words   []int    `json:"words"`
+}
+
+func encode(tokenizer *gotokenizers.Tokenizer, line string, w io.Writer, format string) error {
+	encoding, err := tokenizer.Encode(line)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		return writeJSON(w, encodingOutput{
+			IDs:     encoding.IDs,
+			Tokens:  encoding.Tokens,
+			Offsets: offsetsPairs(encoding),
+			Words:   encoding.Words,
+		})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "INDEX\tID\tTOKEN\tSTART\tEND\tWORD\tTEXT")
+	for i, id := range encoding.IDs {
+		o := encoding.Offsets[i]
+		fmt.Fprintf(tw, "%d\t%d\t%q\t%d\t%d\t%d\t%s\n",
+			i, id, encoding.Tokens[i], o.Start, o.End, encoding.Words[i], offsetsText(line, o))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
+
+// offsetsText returns the quoted text of the line covered by the given
+// offsets, or a placeholder if they do not fit the line.
+func offsetsText(line string, o strutils.ByteOffsets) string {
+	if o.Start < 0 || o.Start > o.End || o.End > len(line) {
+		return "(out of range)"
+	}
+	return strconv.Quote(line[o.Start:o.End])
+}
+
+func offsetsPairs(encoding *encodings.Encoding) [][2]int {
+	pairs := make([][2]int, len(encoding.Offsets))
+	for i, o := range encoding.Offsets {
+		pairs[i] = [2]int{o.Start, o.End}
+	}
+	return pairs
+}
+
+// decode reads a line of IDs, either as a JSON array, or separated by
+// whitespaces or commas.
+func decode(tokenizer *gotokenizers.Tokenizer, line string, w io.Writer, format string) error {
+	ids, err := parseIDs(line)
+	if err != nil {
+		return err
+	}
+	text, err := tokenizer.Decode(ids)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		return writeJSON(w, struct {
+			Text string `json:"text"`
+		}{text})
+	}
+	_, err = fmt.Fprintln(w, text)
+	return err
+}
+
+func parseIDs(line string) ([]int, error) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "[") {
+		var ids []int
+		err := json.Unmarshal([]byte(line), &ids)
+		return ids, err
+	}
+
+	fields := strings.FieldsFunc(line, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	ids := make([]int, len(fields))
+	for i, field := range fields {
+		id, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ID %#v", field)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func count(tokenizer *gotokenizers.Tokenizer, line string, w io.Writer, format string) error {
+	n, err := tokenizer.Count(line)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		return writeJSON(w, struct {
+			Count int `json:"count"`
+		}{n})
+	}
+	_, err = fmt.Fprintln(w, n)
+	return err
+}
+
+type splitOutput struct {
+	String  string        `json:"string"`
+	Offsets [2]int        `json:"offsets"`
+	Tokens  []tokenOutput `json:"tokens,omitempty"`
+}
+
+type tokenOutput struct {
+	ID      int    `json:"id"`
+	Value   string `json:"value"`
+	Offsets [2]int `json:"offsets"`
+}
+
+type normalizationOutput struct {
+	Normalizer string `json:"normalizer"`
+	Normalized string `json:"normalized"`
+}
+
+type preTokenizationOutput struct {
+	PreTokenizer string        `json:"pre_tokenizer"`
+	Splits       []splitOutput `json:"splits"`
+}
+
+type inspectOutput struct {
+	Original        string                  `json:"original"`
+	Normalization   []normalizationOutput   `json:"normalization,omitempty"`
+	Normalized      string                  `json:"normalized"`
+	PreTokenization []preTokenizationOutput `json:"pre_tokenization,omitempty"`
+	PreTokenized    []splitOutput           `json:"pre_tokenized"`
+}
+
+// inspect traces the encoding of the line, printing the output of each
+// pipeline step.
+func inspect(tokenizer *gotokenizers.Tokenizer, line string, w io.Writer, format string) error {
+	trace, err := tokenizer.Trace(line)
+	if err != nil {
+		return err
+	}
+
+	output := inspectOutput{
+		Original:     line,
+		Normalized:   line,
+		PreTokenized: splitsOutput(trace.Tokenization),
+	}
+	for _, step := range trace.Normalization {
+		output.Normalization = append(output.Normalization, normalizationOutput{
+			Normalizer: step.Normalizer,
+			Normalized: step.Normalized,
+		})
+		output.Normalized = step.Normalized
+	}
+	for _, step := range trace.PreTokenization {
+		output.PreTokenization = append(output.PreTokenization, preTokenizationOutput{
+			PreTokenizer: step.PreTokenizer,
+			Splits:       splitsOutput(step.Splits),
+		})
+	}
+
+	if format == "json" {
+		return writeJSON(w, output)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "original:\t%q\n", output.Original)
+	for _, step := range output.Normalization {
+		fmt.Fprintf(tw, "%s:\t%q\n", step.Normalizer, step.Normalized)
+	}
+	for _, step := range output.PreTokenization {
+		values := make([]string, len(step.Splits))
+		for i, so := range step.Splits {
+			values[i] = fmt.Sprintf("%q", so.String)
+		}
+		fmt.Fprintf(tw, "%s:\t%s\n", step.PreTokenizer, strings.Join(values, " "))
+	}
+	fmt.Fprintln(tw, "SPLIT\tSTART\tEND\tTOKENS")
+	for _, so := range output.PreTokenized {
+		values := make([]string, len(so.Tokens))
+		for i, token := range so.Tokens {
+			values[i] = fmt.Sprintf("%q(%d)", token.Value, token.ID)
+		}
+		fmt.Fprintf(tw, "%q\t%d\t%d\t%s\n", so.String, so.Offsets[0], so.Offsets[1], strings.Join(values, " "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
+
+func splitsOutput(splits []pretokenizedstring.OriginalByteSplit) []splitOutput {
+	result := make([]splitOutput, len(splits))
+	for i, split := range splits {
+		so := splitOutput{
+			String:  split.String,
+			Offsets: [2]int{split.Offsets.Start, split.Offsets.End},
+		}
+		if split.Tokens != nil {
+			for _, token := range *split.Tokens {
+				so.Tokens = append(so.Tokens, tokenOutput{
+					ID:      token.ID,
+					Value:   token.Value,
+					Offsets: [2]int{token.Offsets.Start, token.Offsets.End},
+				})
+			}
+		}
+		result[i] = so
+	}
+	return result
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"strings"
+	"testing"
+)
+
+const testTokenizerFile = "../../testdata/tokenizers/bert-tiny.json"
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		args     []string
+		input    string
+		expected string
+	}{
+		{
+			"encode",
+			[]string{"encode", "-tokenizer", testTokenizerFile},
+			"Hello world!\nunaffable\n",
+			`{"ids":[4,5,9],"tokens":["hello","world","!"],"offsets":[[0,5],[6,11],[11,12]],"words":[0,1,2]}` + "\n" +
+				`{"ids":[6,7,8],"tokens":["un","##aff","##able"],"offsets":[[0,2],[2,5],[5,9]],"words":[0,0,0]}` + "\n",
+		},
+		{
+			"decode",
+			[]string{"decode", "-tokenizer", testTokenizerFile, "-format", "table"},
+			"4 5 9\n[6, 7, 8]\n",
+			"hello world!\nunaffable\n",
+		},
+		{
+			"count",
+			[]string{"count", "-tokenizer", testTokenizerFile},
+			"Hello world!\n\nunaffable\n",
+			`{"count":3}` + "\n" + `{"count":0}` + "\n" + `{"count":3}` + "\n",
+		},
+		{
+			"inspect",
+			[]string{"inspect", "-tokenizer", testTokenizerFile},
+			"Hello!\n",
+			`{"original":"Hello!",` +
+				`"normalization":[{"normalizer":"*bertnormalizer.BertNormalizer","normalized":"hello!"}],` +
+				`"normalized":"hello!",` +
+				`"pre_tokenization":[{"pre_tokenizer":"*bertpretokenizer.BertPreTokenizer",` +
+				`"splits":[{"string":"hello","offsets":[0,5]},{"string":"!","offsets":[5,6]}]}],` +
+				`"pre_tokenized":[` +
+				`{"string":"hello","offsets":[0,5],"tokens":[{"id":4,"value":"hello","offsets":[0,5]}]},` +
+				`{"string":"!","offsets":[5,6],"tokens":[{"id":9,"value":"!","offsets":[0,1]}]}]}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := run(tc.args, strings.NewReader(tc.input), &out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := out.String(); actual != tc.expected {
+				t.Errorf("expected\n  %s\nactual\n  %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{
+		{},
+		{"foo", "-tokenizer", testTokenizerFile},
+		{"encode"},
+		{"encode", "-tokenizer", testTokenizerFile, "-format", "xml"},
+	} {
+		err := run(args, strings.NewReader(""), &bytes.Buffer{})
+		if err == nil {
+			t.Errorf("expected error for args %#v, actual nil", args)
+		}
+	}
+}
+
+func TestOffsetsText(t *testing.T) {
+	t.Parallel()
+
+	for o, expected := range map[strutils.ByteOffsets]string{
+		{Start: 0, End: 5}:  `"Hello"`,
+		{Start: 5, End: 5}:  `""`,
+		{Start: 3, End: 6}:  "(out of range)",
+		{Start: 4, End: 2}:  "(out of range)",
+		{Start: -1, End: 2}: "(out of range)",
+	} {
+		if actual := offsetsText("Hello", o); actual != expected {
+			t.Errorf("%v: expected %s, actual %s", o, expected, actual)
+		}
+	}
+}
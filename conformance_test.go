@@ -0,0 +1,113 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gotokenizers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// conformanceFixture is the expected encoding of a single input, as
+// produced by the Python tokenizers library (see
+// testdata/conformance/generate.py). Offsets are expressed in runes.
+//
+// A fixture only made of the input has not been generated yet, and the
+// whole tokenizer is skipped.
+type conformanceFixture struct {
+	Input   string   `json:"input"`
+	IDs     []int    `json:"ids"`
+	Tokens  []string `json:"tokens"`
+	Offsets [][2]int `json:"offsets"`
+	WordIDs []int    `json:"word_ids"`
+}
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	dirs, err := filepath.Glob(filepath.Join("testdata", "conformance", "*", "tokenizer.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) == 0 {
+		t.Fatal("no conformance tokenizers found")
+	}
+
+	for _, tokenizerFile := range dirs {
+		dir := filepath.Dir(tokenizerFile)
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			tokenizer, err := FromFile(tokenizerFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fixtures, err := readConformanceFixtures(filepath.Join(dir, "fixtures.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, fixture := range fixtures {
+				if fixture.IDs == nil {
+					t.Skip("expected outputs not generated: run testdata/conformance/generate.py")
+				}
+			}
+			for _, fixture := range fixtures {
+				encoding, err := tokenizer.Encode(fixture.Input)
+				if err != nil {
+					t.Errorf("%q: %v", fixture.Input, err)
+					continue
+				}
+
+				runeOffsets, err := encoding.RuneOffsets(fixture.Input)
+				if err != nil {
+					t.Errorf("%q: %v", fixture.Input, err)
+					continue
+				}
+				offsets := make([][2]int, len(encoding.Offsets))
+				for i, o := range runeOffsets {
+					offsets[i] = [2]int{o.Start, o.End}
+				}
+
+				checkConformance(t, fixture.Input, "ids", encoding.IDs, fixture.IDs)
+				checkConformance(t, fixture.Input, "tokens", encoding.Tokens, fixture.Tokens)
+				checkConformance(t, fixture.Input, "offsets", offsets, fixture.Offsets)
+				checkConformance(t, fixture.Input, "word ids", encoding.Words, fixture.WordIDs)
+
+				untracked, err := tokenizer.EncodeWithoutOffsets(fixture.Input)
+				if err != nil {
+					t.Errorf("%q: %v", fixture.Input, err)
+					continue
+				}
+				checkConformance(t, fixture.Input, "ids without offsets", untracked.IDs, fixture.IDs)
+				checkConformance(t, fixture.Input, "tokens without offsets", untracked.Tokens, fixture.Tokens)
+				checkConformance(t, fixture.Input, "word ids without offsets", untracked.Words, fixture.WordIDs)
+
+				count, err := tokenizer.Count(fixture.Input)
+				if err != nil {
+					t.Errorf("%q: %v", fixture.Input, err)
+					continue
+				}
+				checkConformance(t, fixture.Input, "count", count, len(fixture.IDs))
+			}
+		})
+	}
+}
+
+func readConformanceFixtures(filename string) ([]conformanceFixture, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []conformanceFixture
+	err = json.Unmarshal(data, &fixtures)
+	return fixtures, err
+}
+
+func checkConformance(t *testing.T, input, what string, actual, expected interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("%q: expected %s %#v, actual %#v", input, what, expected, actual)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package decoders
+
+import "strings"
+
+// Decoder is implemented by any value that has a DecodeChain method,
+// which takes care of converting a sequence of tokens back into
+// human-readable text.
+//
+// The tokens are processed as a chain: each Decoder receives the output of
+// the previous one (if any), and returns a new list of strings, which are
+// eventually concatenated to obtain the final decoded string.
+type Decoder interface {
+	DecodeChain(tokens []string) ([]string, error)
+}
+
+// Decode runs the given Decoder over the tokens, and returns the
+// concatenation of the resulting strings.
+func Decode(d Decoder, tokens []string) (string, error) {
+	decoded, err := d.DecodeChain(tokens)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(decoded, ""), nil
+}
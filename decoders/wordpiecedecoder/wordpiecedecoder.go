@@ -0,0 +1,79 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wordpiecedecoder
+
+import (
+	"github.com/nlpodyssey/gotokenizers/decoders"
+	"strings"
+)
+
+// WordPieceDecoder allows decoding tokens produced by a WordPiece model.
+//
+// Tokens starting with the continuing subword prefix are attached to the
+// previous ones (without the prefix), while all other tokens are separated
+// by a whitespace.
+type WordPieceDecoder struct {
+	// The prefix used for continuing subwords.
+	prefix string
+	// Whether to clean up some tokenization artifacts, mainly spaces
+	// before punctuation, and some abbreviated english forms.
+	cleanup bool
+}
+
+var _ decoders.Decoder = &WordPieceDecoder{}
+
+// New returns a new WordPieceDecoder.
+func New(prefix string, cleanup bool) *WordPieceDecoder {
+	return &WordPieceDecoder{
+		prefix:  prefix,
+		cleanup: cleanup,
+	}
+}
+
+// NewDefault returns a new WordPieceDecoder, with prefix "##" and
+// cleanup enabled.
+func NewDefault() *WordPieceDecoder {
+	return New("##", true)
+}
+
+// DecodeChain removes the continuing subword prefixes, and prepends a
+// whitespace to all the other tokens, except the first one.
+func (d *WordPieceDecoder) DecodeChain(tokens []string) ([]string, error) {
+	decoded := make([]string, len(tokens))
+	for i, token := range tokens {
+		if i != 0 {
+			if strings.HasPrefix(token, d.prefix) {
+				token = strings.Replace(token, d.prefix, "", 1)
+			} else {
+				token = " " + token
+			}
+		}
+		if d.cleanup {
+			token = Cleanup(token)
+		}
+		decoded[i] = token
+	}
+	return decoded, nil
+}
+
+var cleanupReplacer = strings.NewReplacer(
+	" .", ".",
+	" ?", "?",
+	" !", "!",
+	" ,", ",",
+	" ' ", "'",
+	" n't", "n't",
+	" 'm", "'m",
+	" do not", " don't",
+	" 's", "'s",
+	" 've", "'ve",
+	" 're", "'re",
+)
+
+// Cleanup removes some tokenization artifacts from the given string, mainly
+// spaces before punctuation, and some abbreviated english forms.
+func Cleanup(s string) string {
+	return cleanupReplacer.Replace(s)
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wordpiecedecoder
+
+import (
+	"github.com/nlpodyssey/gotokenizers/decoders"
+	"testing"
+)
+
+func TestWordPieceDecoder(t *testing.T) {
+	t.Parallel()
+
+	tokens := []string{"i", "do", "not", "like", "un", "##aff", "##able", "people", "!", "we", "'re", "."}
+
+	t.Run("with cleanup", func(t *testing.T) {
+		decoded, err := decoders.Decode(NewDefault(), tokens)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "i do not like unaffable people! we're."
+		if decoded != expected {
+			t.Errorf("expected %#v, actual %#v", expected, decoded)
+		}
+	})
+
+	t.Run("without cleanup", func(t *testing.T) {
+		decoded, err := decoders.Decode(New("##", false), tokens)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "i do not like unaffable people ! we 're ."
+		if decoded != expected {
+			t.Errorf("expected %#v, actual %#v", expected, decoded)
+		}
+	})
+}
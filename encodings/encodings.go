@@ -4,7 +4,10 @@
 
 package encodings
 
-import "github.com/nlpodyssey/gotokenizers/strutils"
+import (
+	"fmt"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+)
 
 // Encoding represents the output of a Tokenizer.
 type Encoding struct {
@@ -25,6 +28,18 @@ type Encoding struct {
 	AttentionMask []int
 	// A list of overflowing Encoding generated when we got truncated
 	Overflowing []*Encoding
+	// Ranges of tokens covered by each sequence, by sequence ID.
+	// If empty, there is a single sequence covering the entire Encoding.
+	SequenceRanges map[int]TokenRange
+}
+
+// TokenRange represents a (start, end) range of token positions within
+// an Encoding.
+type TokenRange struct {
+	// Start token index, inclusive.
+	Start int
+	// End token index, exclusive.
+	End int
 }
 
 // EncodableToken represents a single token, expected to be part of a sequence
@@ -103,3 +118,245 @@ func (e *Encoding) IsEmpty() bool {
 func (e *Encoding) Len() int {
 	return len(e.IDs)
 }
+
+// RuneOffsets returns the Offsets of this Encoding converted into rune
+// (Unicode code point) positions.
+//
+// The Offsets are expected to be byte offsets relative to the original
+// string of the sequence each token belongs to: originals[i] is the
+// original string of the sequence with ID i. An encoding made of a single
+// sequence only needs one original string. An error is returned if a token
+// belongs to a sequence without an original string.
+func (e *Encoding) RuneOffsets(originals ...string) ([]strutils.RuneOffsets, error) {
+	converters := make([]*strutils.BytesToRuneOffsetConverter, len(originals))
+	offsets := make([]strutils.RuneOffsets, len(e.Offsets))
+	for i, o := range e.Offsets {
+		sequenceID, err := e.originalIndex(i, len(originals))
+		if err != nil {
+			return nil, err
+		}
+		if converters[sequenceID] == nil {
+			converters[sequenceID] = strutils.NewBytesToRuneOffsetConverter(originals[sequenceID])
+		}
+		if offsets[i], err = converters[sequenceID].Convert(o); err != nil {
+			return nil, fmt.Errorf("token %d: %w", i, err)
+		}
+	}
+	return offsets, nil
+}
+
+// UTF16Offsets returns the Offsets of this Encoding converted into UTF-16
+// code unit positions, such as the ones used for indexing JavaScript
+// strings.
+//
+// The originals are interpreted as in RuneOffsets.
+func (e *Encoding) UTF16Offsets(originals ...string) ([]strutils.UTF16Offsets, error) {
+	converters := make([]*strutils.BytesToUTF16OffsetConverter, len(originals))
+	offsets := make([]strutils.UTF16Offsets, len(e.Offsets))
+	for i, o := range e.Offsets {
+		sequenceID, err := e.originalIndex(i, len(originals))
+		if err != nil {
+			return nil, err
+		}
+		if converters[sequenceID] == nil {
+			converters[sequenceID] = strutils.NewBytesToUTF16OffsetConverter(originals[sequenceID])
+		}
+		if offsets[i], err = converters[sequenceID].Convert(o); err != nil {
+			return nil, fmt.Errorf("token %d: %w", i, err)
+		}
+	}
+	return offsets, nil
+}
+
+// originalIndex returns the ID of the sequence containing the given token,
+// checking that it is a valid index for a list of numOriginals original
+// strings. Tokens outside of any sequence are considered part of the
+// sequence 0.
+func (e *Encoding) originalIndex(token, numOriginals int) (int, error) {
+	sequenceID, _ := e.TokenToSequence(token)
+	if sequenceID < 0 || sequenceID >= numOriginals {
+		return 0, fmt.Errorf("no original string for sequence %d", sequenceID)
+	}
+	return sequenceID, nil
+}
+
+// SetSequenceID marks all the tokens of this Encoding as belonging to the
+// sequence with the given ID.
+func (e *Encoding) SetSequenceID(sequenceID int) {
+	e.SequenceRanges = map[int]TokenRange{
+		sequenceID: {Start: 0, End: e.Len()},
+	}
+}
+
+// NumSequences returns the number of sequences combined in this Encoding.
+func (e *Encoding) NumSequences() int {
+	if len(e.SequenceRanges) == 0 {
+		return 1
+	}
+	return len(e.SequenceRanges)
+}
+
+// SequenceRange returns the range of tokens covered by the sequence with
+// the given ID. If the sequence is unknown, the range of the whole
+// Encoding is returned.
+func (e *Encoding) SequenceRange(sequenceID int) TokenRange {
+	if r, ok := e.SequenceRanges[sequenceID]; ok {
+		return r
+	}
+	return TokenRange{Start: 0, End: e.Len()}
+}
+
+// TokenToSequence returns the ID of the sequence containing the given
+// token, and whether it was found.
+func (e *Encoding) TokenToSequence(token int) (int, bool) {
+	if token < 0 || token >= e.Len() {
+		return 0, false
+	}
+	if len(e.SequenceRanges) == 0 {
+		return 0, true
+	}
+	for sequenceID, r := range e.SequenceRanges {
+		if token >= r.Start && token < r.End {
+			return sequenceID, true
+		}
+	}
+	return 0, false
+}
+
+// WordToTokens returns the range of tokens corresponding to the given word
+// index in the given sequence, and whether it was found.
+func (e *Encoding) WordToTokens(word int, sequenceID int) (TokenRange, bool) {
+	start, end := -1, -1
+	r := e.SequenceRange(sequenceID)
+	for i := r.Start; i < r.End; i++ {
+		if e.Words[i] != word {
+			continue
+		}
+		if start == -1 {
+			start = i
+		}
+		end = i + 1
+	}
+	if start == -1 {
+		return TokenRange{}, false
+	}
+	return TokenRange{Start: start, End: end}, true
+}
+
+// WordToChars returns the offsets of the given word index in the given
+// sequence, and whether it was found.
+//
+// Like Offsets, the returned values are byte positions relative to the
+// original string.
+func (e *Encoding) WordToChars(word int, sequenceID int) (strutils.ByteOffsets, bool) {
+	tokens, ok := e.WordToTokens(word, sequenceID)
+	if !ok {
+		return strutils.ByteOffsets{}, false
+	}
+	return strutils.ByteOffsets{
+		Start: e.Offsets[tokens.Start].Start,
+		End:   e.Offsets[tokens.End-1].End,
+	}, true
+}
+
+// TokenToChars returns the ID of the sequence containing the given token,
+// the token offsets, and whether the token was found.
+//
+// Like Offsets, the returned values are byte positions relative to the
+// original string.
+func (e *Encoding) TokenToChars(token int) (int, strutils.ByteOffsets, bool) {
+	sequenceID, ok := e.TokenToSequence(token)
+	if !ok {
+		return 0, strutils.ByteOffsets{}, false
+	}
+	return sequenceID, e.Offsets[token], true
+}
+
+// TokenToWord returns the ID of the sequence containing the given token,
+// the index of the word which the token belongs to, and whether it was
+// found.
+//
+// The lookup is unsuccessful for tokens not associated to any word,
+// such as special tokens.
+func (e *Encoding) TokenToWord(token int) (int, int, bool) {
+	sequenceID, ok := e.TokenToSequence(token)
+	if !ok || e.Words[token] < 0 {
+		return 0, 0, false
+	}
+	return sequenceID, e.Words[token], true
+}
+
+// CharToToken returns the index of the token which contains the given
+// position of the original string of the given sequence, and whether it
+// was found.
+//
+// Like Offsets, the position is a byte index of the original string.
+func (e *Encoding) CharToToken(pos int, sequenceID int) (int, bool) {
+	r := e.SequenceRange(sequenceID)
+	for i := r.Start; i < r.End; i++ {
+		if o := e.Offsets[i]; pos >= o.Start && pos < o.End {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// CharToWord returns the index of the word which contains the given
+// position of the original string of the given sequence, and whether it
+// was found.
+//
+// Like Offsets, the position is a byte index of the original string.
+func (e *Encoding) CharToWord(pos int, sequenceID int) (int, bool) {
+	token, ok := e.CharToToken(pos, sequenceID)
+	if !ok || e.Words[token] < 0 {
+		return 0, false
+	}
+	return e.Words[token], true
+}
+
+// MergeWith appends the content of the other Encoding to this one.
+//
+// If growingOffsets is true, the offsets of the other Encoding are shifted
+// by the end offset of the last token of this Encoding. The sequence ranges
+// of the other Encoding are preserved and shifted accordingly. If only one
+// of the two Encodings has sequence ranges, the other one is considered as
+// the single sequence 0.
+//
+// Overflowing encodings are not merged.
+func (e *Encoding) MergeWith(other *Encoding, growingOffsets bool) {
+	originalLen := e.Len()
+
+	if len(e.SequenceRanges) > 0 || len(other.SequenceRanges) > 0 {
+		if len(e.SequenceRanges) == 0 {
+			e.SequenceRanges = map[int]TokenRange{0: {Start: 0, End: originalLen}}
+		}
+		otherRanges := other.SequenceRanges
+		if len(otherRanges) == 0 {
+			otherRanges = map[int]TokenRange{0: {Start: 0, End: other.Len()}}
+		}
+		for sequenceID, r := range otherRanges {
+			e.SequenceRanges[sequenceID] = TokenRange{
+				Start: originalLen + r.Start,
+				End:   originalLen + r.End,
+			}
+		}
+	}
+
+	startingOffset := 0
+	if growingOffsets && len(e.Offsets) > 0 {
+		startingOffset = e.Offsets[len(e.Offsets)-1].End
+	}
+
+	e.IDs = append(e.IDs, other.IDs...)
+	e.TypeIDs = append(e.TypeIDs, other.TypeIDs...)
+	e.Tokens = append(e.Tokens, other.Tokens...)
+	e.Words = append(e.Words, other.Words...)
+	for _, o := range other.Offsets {
+		e.Offsets = append(e.Offsets, strutils.ByteOffsets{
+			Start: o.Start + startingOffset,
+			End:   o.End + startingOffset,
+		})
+	}
+	e.SpecialTokensMask = append(e.SpecialTokensMask, other.SpecialTokensMask...)
+	e.AttentionMask = append(e.AttentionMask, other.AttentionMask...)
+}
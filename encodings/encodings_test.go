@@ -0,0 +1,283 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encodings
+
+import (
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"reflect"
+	"testing"
+)
+
+func TestEncodingRuneAndUTF16Offsets(t *testing.T) {
+	t.Parallel()
+
+	// "e" + combining acute accent, and an emoji outside the BMP
+	original := "Cafe\u0301 \U0001F600 ok"
+	encoding := EncodingFromEncodableTokens([]EncodableToken{
+		{ID: 1, Token: "Cafe\u0301", Offsets: strutils.ByteOffsets{Start: 0, End: 6}},
+		{ID: 2, Token: "\U0001F600", Offsets: strutils.ByteOffsets{Start: 7, End: 11}},
+		{ID: 3, Token: "ok", Offsets: strutils.ByteOffsets{Start: 12, End: 14}},
+	})
+
+	runeOffsets, err := encoding.RuneOffsets(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, runeOffsets, []strutils.RuneOffsets{
+		{Start: 0, End: 5},
+		{Start: 6, End: 7},
+		{Start: 8, End: 10},
+	})
+	utf16Offsets, err := encoding.UTF16Offsets(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, utf16Offsets, []strutils.UTF16Offsets{
+		{Start: 0, End: 5},
+		{Start: 6, End: 8},
+		{Start: 9, End: 11},
+	})
+
+	// The offsets exceed the length of a shorter original string
+	if _, err := encoding.RuneOffsets("Cafe"); err == nil {
+		t.Error("expected an error for out of range offsets")
+	}
+	if _, err := encoding.UTF16Offsets("Cafe"); err == nil {
+		t.Error("expected an error for out of range offsets")
+	}
+}
+
+func TestEncodingRuneAndUTF16OffsetsOfPair(t *testing.T) {
+	t.Parallel()
+
+	// The offsets of each sequence refer to its own original string.
+	first := "été"
+	second := "😀 ok"
+	encoding := EncodingFromEncodableTokens([]EncodableToken{
+		{ID: 1, Token: "été", Offsets: strutils.ByteOffsets{Start: 0, End: 5}},
+	})
+	encoding.SetSequenceID(0)
+	pair := EncodingFromEncodableTokens([]EncodableToken{
+		{ID: 2, Token: "😀", Offsets: strutils.ByteOffsets{Start: 0, End: 4}},
+		{ID: 3, Token: "ok", Offsets: strutils.ByteOffsets{Start: 5, End: 7}},
+	})
+	pair.SetSequenceID(1)
+	encoding.MergeWith(pair, false)
+
+	runeOffsets, err := encoding.RuneOffsets(first, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, runeOffsets, []strutils.RuneOffsets{
+		{Start: 0, End: 3},
+		{Start: 0, End: 1},
+		{Start: 2, End: 4},
+	})
+	utf16Offsets, err := encoding.UTF16Offsets(first, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, utf16Offsets, []strutils.UTF16Offsets{
+		{Start: 0, End: 3},
+		{Start: 0, End: 2},
+		{Start: 3, End: 5},
+	})
+
+	if _, err := encoding.RuneOffsets(first); err == nil {
+		t.Error("expected an error for the missing original string of sequence 1")
+	}
+	if _, err := encoding.UTF16Offsets(first); err == nil {
+		t.Error("expected an error for the missing original string of sequence 1")
+	}
+}
+
+func TestEncodingNavigation(t *testing.T) {
+	t.Parallel()
+
+	// "Hey unbelievable!" -> ["[CLS]", "hey", "un", "##believ", "##able", "!", "[SEP]"]
+	encoding := &Encoding{
+		IDs:    []int{0, 1, 2, 3, 4, 5, 6},
+		Tokens: []string{"[CLS]", "hey", "un", "##believ", "##able", "!", "[SEP]"},
+		Words:  []int{-1, 0, 1, 1, 1, 2, -1},
+		Offsets: []strutils.ByteOffsets{
+			{Start: 0, End: 0},
+			{Start: 0, End: 3},
+			{Start: 4, End: 6},
+			{Start: 6, End: 12},
+			{Start: 12, End: 16},
+			{Start: 16, End: 17},
+			{Start: 0, End: 0},
+		},
+	}
+
+	t.Run("WordToTokens", func(t *testing.T) {
+		r, ok := encoding.WordToTokens(1, 0)
+		assertEqual(t, ok, true)
+		assertEqual(t, r, TokenRange{Start: 2, End: 5})
+
+		_, ok = encoding.WordToTokens(3, 0)
+		assertEqual(t, ok, false)
+	})
+
+	t.Run("WordToChars", func(t *testing.T) {
+		o, ok := encoding.WordToChars(1, 0)
+		assertEqual(t, ok, true)
+		assertEqual(t, o, strutils.ByteOffsets{Start: 4, End: 16})
+
+		_, ok = encoding.WordToChars(3, 0)
+		assertEqual(t, ok, false)
+	})
+
+	t.Run("TokenToChars", func(t *testing.T) {
+		seq, o, ok := encoding.TokenToChars(3)
+		assertEqual(t, ok, true)
+		assertEqual(t, seq, 0)
+		assertEqual(t, o, strutils.ByteOffsets{Start: 6, End: 12})
+
+		_, _, ok = encoding.TokenToChars(7)
+		assertEqual(t, ok, false)
+	})
+
+	t.Run("TokenToWord", func(t *testing.T) {
+		seq, word, ok := encoding.TokenToWord(4)
+		assertEqual(t, ok, true)
+		assertEqual(t, seq, 0)
+		assertEqual(t, word, 1)
+
+		_, _, ok = encoding.TokenToWord(0)
+		assertEqual(t, ok, false)
+	})
+
+	t.Run("CharToToken", func(t *testing.T) {
+		token, ok := encoding.CharToToken(7, 0)
+		assertEqual(t, ok, true)
+		assertEqual(t, token, 3)
+
+		_, ok = encoding.CharToToken(3, 0)
+		assertEqual(t, ok, false)
+	})
+
+	t.Run("CharToWord", func(t *testing.T) {
+		word, ok := encoding.CharToWord(16, 0)
+		assertEqual(t, ok, true)
+		assertEqual(t, word, 2)
+
+		_, ok = encoding.CharToWord(17, 0)
+		assertEqual(t, ok, false)
+	})
+}
+
+func TestEncodingNavigationWithSequences(t *testing.T) {
+	t.Parallel()
+
+	// Pair of sequences "hi" and "yo" -> ["hi", "yo"]
+	encoding := &Encoding{
+		IDs:    []int{1, 2},
+		Tokens: []string{"hi", "yo"},
+		Words:  []int{0, 0},
+		Offsets: []strutils.ByteOffsets{
+			{Start: 0, End: 2},
+			{Start: 0, End: 2},
+		},
+		SequenceRanges: map[int]TokenRange{
+			0: {Start: 0, End: 1},
+			1: {Start: 1, End: 2},
+		},
+	}
+
+	assertEqual(t, encoding.NumSequences(), 2)
+
+	r, ok := encoding.WordToTokens(0, 1)
+	assertEqual(t, ok, true)
+	assertEqual(t, r, TokenRange{Start: 1, End: 2})
+
+	token, ok := encoding.CharToToken(1, 1)
+	assertEqual(t, ok, true)
+	assertEqual(t, token, 1)
+
+	seq, word, ok := encoding.TokenToWord(1)
+	assertEqual(t, ok, true)
+	assertEqual(t, seq, 1)
+	assertEqual(t, word, 0)
+
+	encoding.SetSequenceID(0)
+	assertEqual(t, encoding.NumSequences(), 1)
+	seq, _, ok = encoding.TokenToChars(1)
+	assertEqual(t, ok, true)
+	assertEqual(t, seq, 0)
+}
+
+func TestEncodingMergeWith(t *testing.T) {
+	t.Parallel()
+
+	newEncoding := func() *Encoding {
+		return EncodingFromEncodableTokens([]EncodableToken{
+			{ID: 1, Token: "a", Offsets: strutils.ByteOffsets{Start: 0, End: 1}, WordIndex: 0},
+			{ID: 2, Token: "b", Offsets: strutils.ByteOffsets{Start: 1, End: 2}, WordIndex: 1},
+		})
+	}
+
+	t.Run("without growing offsets", func(t *testing.T) {
+		encoding := newEncoding()
+		encoding.MergeWith(newEncoding(), false)
+
+		assertEqual(t, encoding.IDs, []int{1, 2, 1, 2})
+		assertEqual(t, encoding.Words, []int{0, 1, 0, 1})
+		assertEqual(t, encoding.Offsets, []strutils.ByteOffsets{
+			{Start: 0, End: 1}, {Start: 1, End: 2}, {Start: 0, End: 1}, {Start: 1, End: 2},
+		})
+		assertEqual(t, encoding.AttentionMask, []int{1, 1, 1, 1})
+		assertEqual(t, encoding.NumSequences(), 1)
+	})
+
+	t.Run("with growing offsets and sequence ranges", func(t *testing.T) {
+		encoding := newEncoding()
+		encoding.SetSequenceID(0)
+		other := newEncoding()
+		other.SetSequenceID(1)
+		encoding.MergeWith(other, true)
+
+		assertEqual(t, encoding.Offsets, []strutils.ByteOffsets{
+			{Start: 0, End: 1}, {Start: 1, End: 2}, {Start: 2, End: 3}, {Start: 3, End: 4},
+		})
+		assertEqual(t, encoding.SequenceRanges, map[int]TokenRange{
+			0: {Start: 0, End: 2},
+			1: {Start: 2, End: 4},
+		})
+	})
+
+	t.Run("range-less encoding into one with ranges", func(t *testing.T) {
+		encoding := newEncoding()
+		encoding.SetSequenceID(1)
+		encoding.MergeWith(newEncoding(), false)
+
+		assertEqual(t, encoding.SequenceRanges, map[int]TokenRange{
+			0: {Start: 2, End: 4},
+			1: {Start: 0, End: 2},
+		})
+	})
+
+	t.Run("encoding with ranges into a range-less one", func(t *testing.T) {
+		encoding := newEncoding()
+		other := newEncoding()
+		other.SetSequenceID(1)
+		encoding.MergeWith(other, false)
+
+		assertEqual(t, encoding.SequenceRanges, map[int]TokenRange{
+			0: {Start: 0, End: 2},
+			1: {Start: 2, End: 4},
+		})
+		seq, ok := encoding.TokenToSequence(0)
+		assertEqual(t, ok, true)
+		assertEqual(t, seq, 0)
+	})
+}
+
+func assertEqual(t *testing.T, actual, expected interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected\n  %#v\nactual\n  %#v", expected, actual)
+	}
+}
@@ -4,6 +4,9 @@
 
 module github.com/nlpodyssey/gotokenizers
 
-go 1.15
+go 1.18
 
-require github.com/dlclark/regexp2 v1.4.0
+require (
+	github.com/dlclark/regexp2 v1.4.0
+	golang.org/x/text v0.22.0
+)
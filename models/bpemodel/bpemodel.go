@@ -44,7 +44,11 @@ type BPEModel struct {
 	unknownFusionEnabled bool
 }
 
-var _ models.Model = &BPEModel{}
+var (
+	_ models.Model        = &BPEModel{}
+	_ models.Counter      = &BPEModel{}
+	_ models.Vocabularied = &BPEModel{}
+)
 
 // New returns a new BPEModel initialized with the given options.
 func New(
@@ -117,6 +121,29 @@ func (m *BPEModel) tokenizeWithCache(sequence string) ([]models.Token, error) {
 	return tokens, nil
 }
 
+// CountTokens returns the number of tokens the given sequence would be
+// tokenized into, without building them.
+func (m *BPEModel) CountTokens(sequence string) (int, error) {
+	if len(sequence) == 0 {
+		return 0, nil
+	}
+
+	if !m.hasDropout() {
+		if hit := m.cache.Get(sequence); hit != nil {
+			return hit.Len(), nil
+		}
+	}
+
+	word, err := m.mergeWord(sequence)
+	if err != nil {
+		return 0, err
+	}
+	if !m.hasDropout() {
+		m.cache.Set(sequence, word)
+	}
+	return word.Len(), nil
+}
+
 func (m *BPEModel) mergeWord(w string) (*Word, error) {
 	word := NewWordWithCapacity(len(w))
 
@@ -188,7 +215,7 @@ func (m *BPEModel) wordToTokens(word *Word) ([]models.Token, error) {
 	for i, wordSymbol := range *word {
 		value, ok := m.vocab.GetString(wordSymbol.ID)
 		if !ok {
-			return nil, fmt.Errorf("ID %d not found in vocabulary", wordSymbol.ID)
+			return nil, &models.UnknownIDError{ID: wordSymbol.ID}
 		}
 		offsetEnd := offsetStart + wordSymbol.Length
 		tokens[i] = models.Token{
@@ -219,3 +246,15 @@ func (m *BPEModel) hasEndOfWordSuffix() bool {
 func (m *BPEModel) hasUnknownToken() bool {
 	return len(m.unknownToken) != 0
 }
+
+// TokenToID returns the vocabulary ID associated to the given token, and
+// whether it was found.
+func (m *BPEModel) TokenToID(token string) (int, bool) {
+	return m.vocab.GetID(token)
+}
+
+// IDToToken returns the token associated to the given vocabulary ID, and
+// whether it was found.
+func (m *BPEModel) IDToToken(id int) (string, bool) {
+	return m.vocab.GetString(id)
+}
@@ -5,10 +5,13 @@
 package bpemodel
 
 import (
+	"encoding/base64"
 	"github.com/nlpodyssey/gotokenizers/models"
 	"github.com/nlpodyssey/gotokenizers/strutils"
 	"github.com/nlpodyssey/gotokenizers/vocabulary"
+	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -88,6 +91,13 @@ func TestTokenizeWithAndWithoutDropout(t *testing.T) {
 	if !reflect.DeepEqual(tokens, expectedTokens) {
 		t.Errorf("expected %+v, actual %+v", expectedTokens, tokens)
 	}
+	count, err := bpe.CountTokens("unrelated")
+	if err != nil {
+		t.Error(err)
+	}
+	if count != len(expectedTokens) {
+		t.Errorf("expected count %d, actual %d", len(expectedTokens), count)
+	}
 
 	// Now set dropout to 1.0. Result should be no merges performed.
 	bpe = New(
@@ -119,6 +129,13 @@ func TestTokenizeWithAndWithoutDropout(t *testing.T) {
 	if !reflect.DeepEqual(tokens, expectedTokens) {
 		t.Errorf("expected %+v, actual %+v", expectedTokens, tokens)
 	}
+	count, err = bpe.CountTokens("unrelated")
+	if err != nil {
+		t.Error(err)
+	}
+	if count != len(expectedTokens) {
+		t.Errorf("expected count %d, actual %d", len(expectedTokens), count)
+	}
 
 	// Now try with dropout between 0 and 1.
 	bpe = New(
@@ -140,3 +157,83 @@ func TestTokenizeWithAndWithoutDropout(t *testing.T) {
 		t.Errorf("expected 0 < len(tokens) < 0, got %v => %+v", len(tokens), tokens)
 	}
 }
+
+// newBenchmarkModel returns a BPEModel whose vocabulary contains all the
+// printable ASCII characters, and whose merges progressively build each
+// of the given words, from left to right.
+func newBenchmarkModel(words []string) *BPEModel {
+	vocab := vocabulary.NewVocabulary()
+	for r := ' '; r <= '~'; r++ {
+		vocab.AddTerm(string(r))
+	}
+	merges := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, word := range words {
+		for i := 2; i <= len(word); i++ {
+			left, right := word[:i-1], word[i-1:i]
+			if seen[left+right] {
+				continue
+			}
+			seen[left+right] = true
+			vocab.AddTerm(left + right)
+			merges = append(merges, left+" "+right)
+		}
+	}
+	mergeMap, err := MergeMapFromStrings(merges, vocab, 0)
+	if err != nil {
+		panic(err)
+	}
+	return New(vocab, mergeMap, 0, 0, "", "", "", false)
+}
+
+var benchmarkEnglishWords = strings.Fields(`the of and to in is was that for it
+	as with be on not he by are this at from or have an they which one you were
+	all her she there would their we him been has when who will no more if out
+	so up said what its about than into them can only other time new some could
+	these two may first then do any like my now over such our man me even most
+	made after also did many before must through back years where much your way
+	well down should because each just those people how too little state good
+	very make world still own see men work long get here between both life being
+	under never day same another know while last might us great old year off
+	come since against go came right used take three tokenization tokenizer`)
+
+func BenchmarkBPEModelTokenizeEnglish(b *testing.B) {
+	model := newBenchmarkModel(benchmarkEnglishWords)
+	words := strings.Fields(strings.Repeat(
+		"the tokenizer was made to split words like these into a few tokens ", 20))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, word := range words {
+			if _, err := model.Tokenize(word); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkBPEModelTokenizeURL(b *testing.B) {
+	model := newBenchmarkModel(append(benchmarkEnglishWords, "https://", "www.", ".com/"))
+	url := "https://www.example.com/" + strings.Repeat("the/tokenizer/were/made/", 40) + "?q=" +
+		strings.Repeat("new", 50)
+	benchmarkBPEModelTokenizeWord(b, model, url)
+}
+
+func BenchmarkBPEModelTokenizeBase64(b *testing.B) {
+	model := newBenchmarkModel(benchmarkEnglishWords)
+	data := make([]byte, 3000)
+	rnd := rand.New(rand.NewSource(42))
+	rnd.Read(data)
+	benchmarkBPEModelTokenizeWord(b, model, base64.StdEncoding.EncodeToString(data))
+}
+
+func benchmarkBPEModelTokenizeWord(b *testing.B, model *BPEModel, word string) {
+	b.SetBytes(int64(len(word)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := model.Tokenize(word); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
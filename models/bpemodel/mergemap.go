@@ -25,7 +25,28 @@ type MergeValue struct {
 	ID int
 }
 
-type symbolIDPair [2]int
+// MergesParseError is the error returned when a merge cannot be read.
+type MergesParseError struct {
+	// Line is the 1-based line number of the merge in a merges file. When
+	// the merges are given as a list (MergeMapFromStrings, MergeMapFromPairs,
+	// or the "merges" of a tokenizer.json file), it is the 1-based index of
+	// the merge in the list instead.
+	Line int
+	// Reason describes the problem.
+	Reason string
+}
+
+func (e *MergesParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+// symbolIDPair packs a pair of Symbol IDs into a single integer, for
+// faster map lookups.
+type symbolIDPair uint64
+
+func newSymbolIDPair(firstID, secondID int) symbolIDPair {
+	return symbolIDPair(uint64(uint32(firstID))<<32 | uint64(uint32(secondID)))
+}
 
 // NewMergeMap a new empty MergeMap.
 func NewMergeMap() *MergeMap {
@@ -34,6 +55,8 @@ func NewMergeMap() *MergeMap {
 }
 
 // MergeMapFromFile reads merges from file.
+//
+// Invalid merges are reported with a *MergesParseError.
 func MergeMapFromFile(
 	filename string,
 	vocab *vocabulary.Vocabulary,
@@ -58,43 +81,109 @@ func MergeMapFromFile(
 		if strings.HasPrefix(line, "#version") {
 			continue
 		}
-		terms := strings.Split(line, " ")
-		if len(terms) != 2 {
-			return nil, fmt.Errorf("line %d: malformed merges", lineCount)
+		if err = m.addMerge(line, lineCount, rank, vocab, prefixLength); err != nil {
+			return nil, err
 		}
+		rank++
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
 
-		leftID, leftOK := vocab.GetID(terms[0])
-		if !leftOK {
-			return nil, fmt.Errorf("line %d: left merge token is out of vocabulary", lineCount)
-		}
-		rightID, rightOK := vocab.GetID(terms[1])
-		if !rightOK {
-			return nil, fmt.Errorf("line %d: right merge token is out of vocabulary", lineCount)
+	return m, nil
+}
+
+// MergeMapFromStrings builds a new MergeMap from a list of merges, each one
+// represented by a string containing two space-separated terms.
+//
+// The rank of each merge is its index in the list. Invalid merges are
+// reported with a *MergesParseError.
+func MergeMapFromStrings(
+	merges []string,
+	vocab *vocabulary.Vocabulary,
+	prefixLength int,
+) (*MergeMap, error) {
+	m := NewMergeMap()
+	for rank, merge := range merges {
+		if err := m.addMerge(merge, rank+1, rank, vocab, prefixLength); err != nil {
+			return nil, err
 		}
+	}
+	return m, nil
+}
 
-		mergedTerm := fmt.Sprintf("%s%s", terms[0], terms[1][prefixLength:])
-		mergedID, mergedOK := vocab.GetID(mergedTerm)
-		if !mergedOK {
-			return nil, fmt.Errorf("line %d: merged token is out of vocabulary", lineCount)
+// MergeMapFromPairs builds a new MergeMap from a list of merges, each one
+// represented by the pair of terms to merge.
+//
+// Unlike MergeMapFromStrings, the terms can contain spaces.
+//
+// The rank of each merge is its index in the list. Invalid merges are
+// reported with a *MergesParseError.
+func MergeMapFromPairs(
+	merges [][2]string,
+	vocab *vocabulary.Vocabulary,
+	prefixLength int,
+) (*MergeMap, error) {
+	m := NewMergeMap()
+	for rank, merge := range merges {
+		err := m.addPair(merge[0], merge[1], rank+1, rank, vocab, prefixLength)
+		if err != nil {
+			return nil, err
 		}
+	}
+	return m, nil
+}
 
-		m.Set(leftID, rightID, MergeValue{Rank: rank, ID: mergedID})
-		rank++
+func (m *MergeMap) addMerge(
+	line string,
+	lineCount int,
+	rank int,
+	vocab *vocabulary.Vocabulary,
+	prefixLength int,
+) error {
+	terms := strings.Split(line, " ")
+	if len(terms) != 2 {
+		return &MergesParseError{Line: lineCount, Reason: "malformed merges"}
 	}
-	if err = scanner.Err(); err != nil {
-		return nil, err
+	return m.addPair(terms[0], terms[1], lineCount, rank, vocab, prefixLength)
+}
+
+func (m *MergeMap) addPair(
+	left, right string,
+	lineCount int,
+	rank int,
+	vocab *vocabulary.Vocabulary,
+	prefixLength int,
+) error {
+	leftID, leftOK := vocab.GetID(left)
+	if !leftOK {
+		return &MergesParseError{Line: lineCount, Reason: "left merge token is out of vocabulary"}
+	}
+	rightID, rightOK := vocab.GetID(right)
+	if !rightOK {
+		return &MergesParseError{Line: lineCount, Reason: "right merge token is out of vocabulary"}
+	}
+	if len(right) < prefixLength {
+		return &MergesParseError{Line: lineCount, Reason: "right merge token is shorter than the prefix"}
 	}
 
-	return m, nil
+	mergedTerm := fmt.Sprintf("%s%s", left, right[prefixLength:])
+	mergedID, mergedOK := vocab.GetID(mergedTerm)
+	if !mergedOK {
+		return &MergesParseError{Line: lineCount, Reason: "merged token is out of vocabulary"}
+	}
+
+	m.Set(leftID, rightID, MergeValue{Rank: rank, ID: mergedID})
+	return nil
 }
 
 // Get returns a value associated to the given pair of ID, and whether
 // the value exists in the map.
 func (m *MergeMap) Get(firstID, secondID int) (MergeValue, bool) {
-	v, ok := (*m)[symbolIDPair{firstID, secondID}]
+	v, ok := (*m)[newSymbolIDPair(firstID, secondID)]
 	return v, ok
 }
 
 func (m *MergeMap) Set(firstID, secondID int, v MergeValue) {
-	(*m)[symbolIDPair{firstID, secondID}] = v
+	(*m)[newSymbolIDPair(firstID, secondID)] = v
 }
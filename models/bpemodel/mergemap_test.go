@@ -5,6 +5,7 @@
 package bpemodel
 
 import (
+	"errors"
 	"github.com/nlpodyssey/gotokenizers/vocabulary"
 	"reflect"
 	"testing"
@@ -34,9 +35,9 @@ func TestMergeMapFromFile(t *testing.T) {
 		t.Fatal(err)
 	}
 	expected := MergeMap{
-		symbolIDPair{0, 1}: MergeValue{Rank: 0, ID: 6},
-		symbolIDPair{2, 3}: MergeValue{Rank: 1, ID: 7},
-		symbolIDPair{4, 5}: MergeValue{Rank: 2, ID: 8},
+		newSymbolIDPair(0, 1): MergeValue{Rank: 0, ID: 6},
+		newSymbolIDPair(2, 3): MergeValue{Rank: 1, ID: 7},
+		newSymbolIDPair(4, 5): MergeValue{Rank: 2, ID: 8},
 	}
 	if !reflect.DeepEqual(*m, expected) {
 		t.Errorf("expected:\n  %#v\nactual:\n  %#v\n", expected, *m)
@@ -67,11 +68,81 @@ func TestMergeMapFromFileWithPrefixLength(t *testing.T) {
 		t.Fatal(err)
 	}
 	expected := MergeMap{
-		symbolIDPair{0, 1}: MergeValue{Rank: 0, ID: 6},
-		symbolIDPair{2, 3}: MergeValue{Rank: 1, ID: 7},
-		symbolIDPair{4, 5}: MergeValue{Rank: 2, ID: 8},
+		newSymbolIDPair(0, 1): MergeValue{Rank: 0, ID: 6},
+		newSymbolIDPair(2, 3): MergeValue{Rank: 1, ID: 7},
+		newSymbolIDPair(4, 5): MergeValue{Rank: 2, ID: 8},
 	}
 	if !reflect.DeepEqual(*m, expected) {
 		t.Errorf("expected:\n  %#v\nactual:\n  %#v\n", expected, *m)
 	}
 }
+
+func TestMergeMapFromStrings(t *testing.T) {
+	t.Parallel()
+
+	vocabTerms := []string{
+		"ab",     // 0
+		"cd",     // 1
+		"efg",    // 2
+		"hij",    // 3
+		"abcd",   // 4
+		"efghij", // 5
+	}
+	vocab := vocabulary.NewVocabulary()
+	for _, term := range vocabTerms {
+		vocab.AddTerm(term)
+	}
+
+	m, err := MergeMapFromStrings([]string{"ab cd", "efg hij"}, vocab, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MergeMap{
+		newSymbolIDPair(0, 1): MergeValue{Rank: 0, ID: 4},
+		newSymbolIDPair(2, 3): MergeValue{Rank: 1, ID: 5},
+	}
+	if !reflect.DeepEqual(*m, expected) {
+		t.Errorf("expected:\n  %#v\nactual:\n  %#v\n", expected, *m)
+	}
+
+	_, err = MergeMapFromStrings([]string{"ab cd", "ab"}, vocab, 0)
+	if err == nil || err.Error() != "line 2: malformed merges" {
+		t.Errorf("expected malformed merges error, actual %#v", err)
+	}
+	var parseErr *MergesParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 2 || parseErr.Reason != "malformed merges" {
+		t.Errorf("expected *MergesParseError, actual %#v", err)
+	}
+
+	_, err = MergeMapFromStrings([]string{"ab xy"}, vocab, 0)
+	if !errors.As(err, &parseErr) || parseErr.Line != 1 || parseErr.Reason != "right merge token is out of vocabulary" {
+		t.Errorf("expected *MergesParseError, actual %#v", err)
+	}
+}
+
+func TestMergeMapFromPairs(t *testing.T) {
+	t.Parallel()
+
+	vocab := vocabulary.NewVocabulary()
+	for _, term := range []string{"a", " ", "b", "a ", "a b"} {
+		vocab.AddTerm(term)
+	}
+
+	m, err := MergeMapFromPairs([][2]string{{"a", " "}, {"a ", "b"}}, vocab, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MergeMap{
+		newSymbolIDPair(0, 1): MergeValue{Rank: 0, ID: 3},
+		newSymbolIDPair(3, 2): MergeValue{Rank: 1, ID: 4},
+	}
+	if !reflect.DeepEqual(*m, expected) {
+		t.Errorf("expected:\n  %#v\nactual:\n  %#v\n", expected, *m)
+	}
+
+	_, err = MergeMapFromPairs([][2]string{{"a", " "}, {"b", "x"}}, vocab, 0)
+	var parseErr *MergesParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 2 || parseErr.Reason != "right merge token is out of vocabulary" {
+		t.Errorf("expected *MergesParseError, actual %#v", err)
+	}
+}
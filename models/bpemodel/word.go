@@ -4,10 +4,7 @@
 
 package bpemodel
 
-import (
-	"container/heap"
-	"math/rand"
-)
+import "math/rand"
 
 // Symbol is an abstract reference to a sequence of characters.
 type Symbol struct {
@@ -25,7 +22,7 @@ type WordSymbol struct {
 	// Prev is the index of the previous symbol in the Word.
 	// -1 means no previous symbol.
 	Prev int
-	// Prev is the index of the next symbol in the Word.
+	// Next is the index of the next symbol in the Word.
 	// -1 means no next symbol.
 	Next int
 }
@@ -47,8 +44,9 @@ func (s *WordSymbol) HasNext() bool {
 	return s.Next != -1
 }
 
-// Word is a slice of WordSymbol.
-type Word []*WordSymbol
+// Word is a sequence of WordSymbol values, linked by their Prev and Next
+// indices.
+type Word []WordSymbol
 
 // NewWord returns a new empty Word.
 func NewWord() *Word {
@@ -66,41 +64,42 @@ func (w *Word) Len() int {
 	return len(*w)
 }
 
-func (w *Word) getSymbolID(index int) int {
-	return (*w)[index].ID
-}
-
 // Add appends a new symbol to the Word.
 func (w *Word) Add(symbolID, byteLen int) {
-	sym := &WordSymbol{
+	prev := w.Len() - 1
+	if prev != -1 {
+		(*w)[prev].Next = w.Len()
+	}
+	*w = append(*w, WordSymbol{
 		Symbol: Symbol{
 			ID:     symbolID,
 			Length: byteLen,
 		},
-		Prev: w.Len() - 1,
+		Prev: prev,
 		Next: -1,
-	}
-	if sym.Prev != -1 {
-		(*w)[sym.Prev].Next = w.Len()
-	}
-	*w = append(*w, sym)
+	})
 }
 
+// MergeAll applies all the possible merges to the symbols of the Word,
+// in order of rank, and position.
+//
+// If dropout is greater than zero, each merge is skipped with the given
+// probability.
 func (w *Word) MergeAll(merges *MergeMap, dropout float64) {
-	symbolsLen := w.Len()
-	queue := make(WordMergeHeap, 0, symbolsLen)
-	skip := make([]WordMerge, 0, symbolsLen)
-
-	lastSymbolIndex := symbolsLen - 1
-	for index := 0; index < lastSymbolIndex; index++ {
-		if m, ok := merges.Get(w.getSymbolID(index), w.getSymbolID(index+1)); ok {
-			heap.Push(&queue, WordMerge{MergeValue: m, Pos: index})
+	symbols := *w
+	queue := make(WordMergeHeap, 0, len(symbols))
+	var skip []WordMerge
+
+	for index := 0; index+1 < len(symbols); index++ {
+		if m, ok := merges.Get(symbols[index].ID, symbols[index+1].ID); ok {
+			queue = append(queue, WordMerge{MergeValue: m, Pos: index})
 		}
 	}
+	queue.init()
 
 	hasDropout := dropout > 0
 	for queue.Len() > 0 {
-		top := heap.Pop(&queue).(WordMerge)
+		top := queue.Pop()
 
 		if hasDropout && rand.Float64() < dropout {
 			skip = append(skip, top)
@@ -109,61 +108,68 @@ func (w *Word) MergeAll(merges *MergeMap, dropout float64) {
 
 		// Re-insert the skipped elements
 		for _, s := range skip {
-			heap.Push(&queue, s)
+			queue.Push(s)
 		}
 		skip = skip[:0] // empty `skip` without reallocating memory
 
-		if (*w)[top.Pos].Length == 0 || !(*w)[top.Pos].HasNext() {
+		current := &symbols[top.Pos]
+		if current.Length == 0 || !current.HasNext() {
 			// Do nothing if the symbol is empty, or if it's the last symbol
 			continue
 		}
 
-		nextPos := (*w)[top.Pos].Next
-		right := (*w)[nextPos]
+		nextPos := current.Next
+		right := symbols[nextPos]
 
 		// Make sure we are not processing an expired queue entry
-		if m, ok := merges.Get((*w)[top.Pos].ID, right.ID); !ok || m.ID != top.ID {
+		if m, ok := merges.Get(current.ID, right.ID); !ok || m.ID != top.ID {
 			continue
 		}
 
 		// Otherwise, let's merge
-		(*w)[top.Pos].MergeWith(right, top.ID)
+		current.MergeWith(&right, top.ID)
 		// Tag the right part as removed
-		(*w)[nextPos].Length = 0
+		symbols[nextPos].Length = 0
 
 		// Update `prev` on the new `next` to the current pos
-		if right.HasNext() && right.Next < w.Len() {
-			(*w)[right.Next].Prev = top.Pos
+		if right.HasNext() {
+			symbols[right.Next].Prev = top.Pos
 		}
 
 		// Insert the new pair formed with the previous symbol
-		current := (*w)[top.Pos]
 		if current.HasPrev() {
-			prevSymbol := (*w)[current.Prev]
-			if m, ok := merges.Get(prevSymbol.ID, current.ID); ok {
-				heap.Push(&queue, WordMerge{MergeValue: m, Pos: current.Prev})
+			if m, ok := merges.Get(symbols[current.Prev].ID, current.ID); ok {
+				queue.Push(WordMerge{MergeValue: m, Pos: current.Prev})
 			}
 		}
 
 		// Insert the new pair formed with the next symbol
-		if current.HasNext() && current.Next < w.Len() {
-			nextSymbol := (*w)[current.Next]
-			if m, ok := merges.Get(current.ID, nextSymbol.ID); ok {
-				heap.Push(&queue, WordMerge{MergeValue: m, Pos: top.Pos})
+		if current.HasNext() {
+			if m, ok := merges.Get(current.ID, symbols[current.Next].ID); ok {
+				queue.Push(WordMerge{MergeValue: m, Pos: top.Pos})
 			}
 		}
 	}
 
-	// Filter out the removed symbols
-	for i := 0; i < w.Len(); {
-		if (*w)[i].Length == 0 {
-			w.removeSymbol(i)
+	w.removeEmptySymbols()
+}
+
+// removeEmptySymbols filters out the symbols removed by merges, in a single
+// pass, updating the Prev and Next indices accordingly.
+func (w *Word) removeEmptySymbols() {
+	symbols := *w
+	n := 0
+	for _, s := range symbols {
+		if s.Length == 0 {
 			continue
 		}
-		i++
+		s.Prev = n - 1
+		s.Next = n + 1
+		symbols[n] = s
+		n++
 	}
-}
-
-func (w *Word) removeSymbol(index int) {
-	*w = append((*w)[:index], (*w)[index+1:]...)
+	if n > 0 {
+		symbols[n-1].Next = -1
+	}
+	*w = symbols[:n]
 }
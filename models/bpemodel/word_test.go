@@ -5,6 +5,7 @@
 package bpemodel
 
 import (
+	"math/rand"
 	"reflect"
 	"testing"
 )
@@ -33,7 +34,7 @@ func TestWordAdd(t *testing.T) {
 
 	w.Add(11, 2)
 	expected := Word{
-		&WordSymbol{Symbol: Symbol{ID: 11, Length: 2}, Prev: -1, Next: -1},
+		WordSymbol{Symbol: Symbol{ID: 11, Length: 2}, Prev: -1, Next: -1},
 	}
 	if !reflect.DeepEqual(*w, expected) {
 		t.Errorf("expected %#v, actual %#v", expected, *w)
@@ -41,8 +42,8 @@ func TestWordAdd(t *testing.T) {
 
 	w.Add(22, 3)
 	expected = Word{
-		&WordSymbol{Symbol: Symbol{ID: 11, Length: 2}, Prev: -1, Next: 1},
-		&WordSymbol{Symbol: Symbol{ID: 22, Length: 3}, Prev: 0, Next: -1},
+		WordSymbol{Symbol: Symbol{ID: 11, Length: 2}, Prev: -1, Next: 1},
+		WordSymbol{Symbol: Symbol{ID: 22, Length: 3}, Prev: 0, Next: -1},
 	}
 	if !reflect.DeepEqual(*w, expected) {
 		t.Errorf("expected %#v, actual %#v", expected, *w)
@@ -50,11 +51,108 @@ func TestWordAdd(t *testing.T) {
 
 	w.Add(33, 4)
 	expected = Word{
-		&WordSymbol{Symbol: Symbol{ID: 11, Length: 2}, Prev: -1, Next: 1},
-		&WordSymbol{Symbol: Symbol{ID: 22, Length: 3}, Prev: 0, Next: 2},
-		&WordSymbol{Symbol: Symbol{ID: 33, Length: 4}, Prev: 1, Next: -1},
+		WordSymbol{Symbol: Symbol{ID: 11, Length: 2}, Prev: -1, Next: 1},
+		WordSymbol{Symbol: Symbol{ID: 22, Length: 3}, Prev: 0, Next: 2},
+		WordSymbol{Symbol: Symbol{ID: 33, Length: 4}, Prev: 1, Next: -1},
 	}
 	if !reflect.DeepEqual(*w, expected) {
 		t.Errorf("expected %#v, actual %#v", expected, *w)
 	}
 }
+
+func TestWordMergeAll(t *testing.T) {
+	t.Parallel()
+
+	// Random merges over a small alphabet of symbols, with IDs from 0 to 3
+	rnd := rand.New(rand.NewSource(1))
+	merges := NewMergeMap()
+	nextID := 4
+	for rank := 0; rank < 40; rank++ {
+		first, second := rnd.Intn(nextID), rnd.Intn(nextID)
+		if _, exists := merges.Get(first, second); exists {
+			continue
+		}
+		merges.Set(first, second, MergeValue{Rank: rank, ID: nextID})
+		nextID++
+	}
+
+	for i := 0; i < 500; i++ {
+		ids := make([]int, rnd.Intn(30))
+		for j := range ids {
+			ids[j] = rnd.Intn(4)
+		}
+
+		w := NewWord()
+		for _, id := range ids {
+			w.Add(id, 1)
+		}
+		w.MergeAll(merges, 0)
+
+		expected := naiveMergeAll(ids, merges)
+		actual := make([]Symbol, w.Len())
+		for j, s := range *w {
+			actual[j] = s.Symbol
+			if s.Prev != j-1 || (j < w.Len()-1 && s.Next != j+1) || (j == w.Len()-1 && s.Next != -1) {
+				t.Fatalf("%v: bad links for symbol %d: %#v", ids, j, s)
+			}
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("%v: expected %v, actual %v", ids, expected, actual)
+		}
+	}
+}
+
+// naiveMergeAll repeatedly applies the merge with the lowest rank, and
+// leftmost position, until no more merges are possible.
+func naiveMergeAll(ids []int, merges *MergeMap) []Symbol {
+	symbols := make([]Symbol, len(ids))
+	for i, id := range ids {
+		symbols[i] = Symbol{ID: id, Length: 1}
+	}
+	for {
+		best := -1
+		var bestMerge MergeValue
+		for i := 0; i+1 < len(symbols); i++ {
+			m, ok := merges.Get(symbols[i].ID, symbols[i+1].ID)
+			if ok && (best == -1 || m.Rank < bestMerge.Rank) {
+				best, bestMerge = i, m
+			}
+		}
+		if best == -1 {
+			return symbols
+		}
+		symbols[best] = Symbol{
+			ID:     bestMerge.ID,
+			Length: symbols[best].Length + symbols[best+1].Length,
+		}
+		symbols = append(symbols[:best+1], symbols[best+2:]...)
+	}
+}
+
+func TestWordMergeHeap(t *testing.T) {
+	t.Parallel()
+
+	h := make(WordMergeHeap, 0)
+	for _, m := range []WordMerge{
+		{MergeValue: MergeValue{Rank: 3}, Pos: 0},
+		{MergeValue: MergeValue{Rank: 1}, Pos: 5},
+		{MergeValue: MergeValue{Rank: 2}, Pos: 1},
+		{MergeValue: MergeValue{Rank: 1}, Pos: 2},
+	} {
+		h.Push(m)
+	}
+
+	var actual []WordMerge
+	for h.Len() > 0 {
+		actual = append(actual, h.Pop())
+	}
+	expected := []WordMerge{
+		{MergeValue: MergeValue{Rank: 1}, Pos: 2},
+		{MergeValue: MergeValue{Rank: 1}, Pos: 5},
+		{MergeValue: MergeValue{Rank: 2}, Pos: 1},
+		{MergeValue: MergeValue{Rank: 3}, Pos: 0},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %#v, actual %#v", expected, actual)
+	}
+}
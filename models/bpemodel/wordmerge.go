@@ -4,41 +4,79 @@
 
 package bpemodel
 
-import "container/heap"
-
+// WordMerge is a candidate merge of the symbol at position Pos of a Word
+// with the next one.
 type WordMerge struct {
 	MergeValue
 	Pos int
 }
 
+// WordMergeHeap is a min-heap of WordMerge values, ordered first on the
+// rank, and on the position otherwise.
 type WordMergeHeap []WordMerge
 
-var _ heap.Interface = &WordMergeHeap{}
-
 func (h *WordMergeHeap) Len() int {
 	return len(*h)
 }
 
-func (h *WordMergeHeap) Less(i, j int) bool {
-	// By manually implementing this, we make the heap a min-heap,
-	// ordered first on the rank, and on the pos otherwise.
-	if (*h)[i].Rank != (*h)[j].Rank {
-		return (*h)[i].Rank < (*h)[j].Rank
+// Push adds a new WordMerge to the heap.
+func (h *WordMergeHeap) Push(m WordMerge) {
+	*h = append(*h, m)
+	h.up(len(*h) - 1)
+}
+
+// Pop removes and returns the minimum WordMerge from the heap.
+// It panics if the heap is empty.
+func (h *WordMergeHeap) Pop() WordMerge {
+	old := *h
+	lastIndex := len(old) - 1
+	old[0], old[lastIndex] = old[lastIndex], old[0]
+	top := old[lastIndex]
+	*h = old[:lastIndex]
+	h.down(0)
+	return top
+}
+
+// init establishes the heap invariants in O(n).
+func (h *WordMergeHeap) init() {
+	for i := len(*h)/2 - 1; i >= 0; i-- {
+		h.down(i)
 	}
-	return (*h)[i].Pos < (*h)[j].Pos
 }
 
-func (h *WordMergeHeap) Swap(i, j int) {
-	(*h)[i], (*h)[j] = (*h)[j], (*h)[i]
+func (h *WordMergeHeap) less(i, j int) bool {
+	a, b := (*h)[i], (*h)[j]
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return a.Pos < b.Pos
 }
 
-func (h *WordMergeHeap) Push(x interface{}) {
-	*h = append(*h, x.(WordMerge))
+func (h *WordMergeHeap) up(j int) {
+	for j > 0 {
+		i := (j - 1) / 2 // parent
+		if !h.less(j, i) {
+			break
+		}
+		(*h)[i], (*h)[j] = (*h)[j], (*h)[i]
+		j = i
+	}
 }
 
-func (h *WordMergeHeap) Pop() interface{} {
-	lastIndex := len(*h) - 1
-	x := (*h)[lastIndex]
-	*h = (*h)[0:lastIndex]
-	return x
+func (h *WordMergeHeap) down(i int) {
+	n := len(*h)
+	for {
+		j := 2*i + 1 // left child
+		if j >= n {
+			break
+		}
+		if right := j + 1; right < n && h.less(right, j) {
+			j = right
+		}
+		if !h.less(j, i) {
+			break
+		}
+		(*h)[i], (*h)[j] = (*h)[j], (*h)[i]
+		i = j
+	}
 }
@@ -4,7 +4,10 @@
 
 package models
 
-import "github.com/nlpodyssey/gotokenizers/strutils"
+import (
+	"fmt"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+)
 
 // Model represents a model used during Tokenization (like BPE or Word or Unigram).
 type Model interface {
@@ -13,8 +16,37 @@ type Model interface {
 	Tokenize(sequence string) ([]Token, error)
 }
 
+// Vocabularied is optionally implemented by a Model which can convert
+// tokens to IDs and back, such as the ones based on a vocabulary.
+type Vocabularied interface {
+	// TokenToID returns the ID associated to the given token, and whether
+	// it was found.
+	TokenToID(token string) (int, bool)
+	// IDToToken returns the token associated to the given ID, and whether
+	// it was found.
+	IDToToken(id int) (string, bool)
+}
+
+// Counter is optionally implemented by a Model which can count the Tokens
+// of a sequence without building them.
+type Counter interface {
+	// CountTokens returns the number of Tokens the given sequence would
+	// be tokenized into.
+	CountTokens(sequence string) (int, error)
+}
+
 type Token struct {
 	ID      int
 	Value   string
 	Offsets strutils.ByteOffsets
 }
+
+// UnknownIDError is the error returned when an ID is not found in the
+// vocabulary.
+type UnknownIDError struct {
+	ID int
+}
+
+func (e *UnknownIDError) Error() string {
+	return fmt.Sprintf("ID %d not found in vocabulary", e.ID)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/nlpodyssey/gotokenizers/models"
 	"github.com/nlpodyssey/gotokenizers/strutils"
 	"github.com/nlpodyssey/gotokenizers/vocabulary"
+	"unicode/utf8"
 )
 
 var ErrUnknownTokenOutOfVocabulary = fmt.Errorf("the provided unk token is out of vocabulary")
@@ -27,7 +28,11 @@ type WordPieceModel struct {
 	maxInputCharsPerWord int
 }
 
-var _ models.Model = &WordPieceModel{}
+var (
+	_ models.Model        = &WordPieceModel{}
+	_ models.Counter      = &WordPieceModel{}
+	_ models.Vocabularied = &WordPieceModel{}
+)
 
 func New(
 	vocab *vocabulary.Vocabulary,
@@ -53,26 +58,62 @@ func NewDefault() *WordPieceModel {
 }
 
 func (m *WordPieceModel) Tokenize(sequence string) ([]models.Token, error) {
-	if len([]rune(sequence)) > m.maxInputCharsPerWord {
-		unkTokenID, unkTokenExists := m.vocab.GetID(m.unknownToken)
-		if !unkTokenExists {
-			return nil, ErrUnknownTokenOutOfVocabulary
-		}
-		return []models.Token{{
-			ID:      unkTokenID,
-			Value:   m.unknownToken,
-			Offsets: strutils.ByteOffsets{Start: 0, End: len(sequence)},
-		}}, nil
+	subTokens := make([]models.Token, 0)
+	ok := m.forEachPiece(sequence, func(id int, value string, start, end int) {
+		subTokens = append(subTokens, models.Token{
+			ID:      id,
+			Value:   value,
+			Offsets: strutils.ByteOffsets{Start: start, End: end},
+		})
+	})
+	if ok {
+		return subTokens, nil
 	}
 
-	isBad := false
-	start := 0
-	subTokens := make([]models.Token, 0)
+	unkTokenID, unkTokenExists := m.vocab.GetID(m.unknownToken)
+	if !unkTokenExists {
+		return nil, ErrUnknownTokenOutOfVocabulary
+	}
+	return []models.Token{{
+		ID:      unkTokenID,
+		Value:   m.unknownToken,
+		Offsets: strutils.ByteOffsets{Start: 0, End: len(sequence)},
+	}}, nil
+}
+
+// CountTokens returns the number of tokens the given sequence would be
+// tokenized into, without building them.
+func (m *WordPieceModel) CountTokens(sequence string) (int, error) {
+	count := 0
+	ok := m.forEachPiece(sequence, func(int, string, int, int) {
+		count++
+	})
+	if ok {
+		return count, nil
+	}
+	if _, unkTokenExists := m.vocab.GetID(m.unknownToken); !unkTokenExists {
+		return 0, ErrUnknownTokenOutOfVocabulary
+	}
+	return 1, nil
+}
+
+// forEachPiece splits the sequence into the longest pieces found in the
+// vocabulary, calling f for each one of them, in order. It returns false
+// if the sequence is too long, or it cannot be entirely split, meaning
+// that it must be replaced by the unknown token; in the latter case, f
+// might have already been called for some pieces.
+func (m *WordPieceModel) forEachPiece(
+	sequence string,
+	f func(id int, value string, start, end int),
+) bool {
+	if utf8.RuneCountInString(sequence) > m.maxInputCharsPerWord {
+		return false
+	}
 
+	start := 0
 	for start < len(sequence) {
 		end := len(sequence)
-		hasCurToken := false
-		var curToken models.Token
+		found := false
 
 		for start < end {
 			subStr := sequence[start:end]
@@ -82,42 +123,31 @@ func (m *WordPieceModel) Tokenize(sequence string) ([]models.Token, error) {
 			}
 
 			if id, ok := m.vocab.GetID(subStr); ok {
-				hasCurToken = true
-				curToken = models.Token{
-					ID:      id,
-					Value:   subStr,
-					Offsets: strutils.ByteOffsets{Start: start, End: end},
-				}
+				found = true
+				f(id, subStr, start, end)
 				break
 			}
 
-			if len(subStr) > 0 {
-				end -= len(string(subStr[len(subStr)-1]))
-			} else {
-				end--
-			}
+			_, lastRuneSize := utf8.DecodeLastRuneInString(sequence[start:end])
+			end -= lastRuneSize
 		}
 
-		if !hasCurToken {
-			isBad = true
-			break
+		if !found {
+			return false
 		}
-
-		subTokens = append(subTokens, curToken)
 		start = end
 	}
+	return true
+}
 
-	if isBad {
-		unkTokenID, unkTokenExists := m.vocab.GetID(m.unknownToken)
-		if !unkTokenExists {
-			return nil, ErrUnknownTokenOutOfVocabulary
-		}
-		return []models.Token{{
-			ID:      unkTokenID,
-			Value:   m.unknownToken,
-			Offsets: strutils.ByteOffsets{Start: 0, End: len(sequence)},
-		}}, nil
-	}
+// TokenToID returns the vocabulary ID associated to the given token, and
+// whether it was found.
+func (m *WordPieceModel) TokenToID(token string) (int, bool) {
+	return m.vocab.GetID(token)
+}
 
-	return subTokens, nil
+// IDToToken returns the token associated to the given vocabulary ID, and
+// whether it was found.
+func (m *WordPieceModel) IDToToken(id int) (string, bool) {
+	return m.vocab.GetString(id)
 }
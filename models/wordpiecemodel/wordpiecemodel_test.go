@@ -29,6 +29,8 @@ func TestWordPieceModelTokenize(t *testing.T) {
 		"gamma",            // 9
 		"##gamma",          // 10
 		"veryverylongterm", // 11
+		"日本",               // 12
+		"##語",              // 13
 	}
 	vocab := vocabulary.NewVocabulary()
 	for _, term := range terms {
@@ -79,6 +81,13 @@ func TestWordPieceModelTokenize(t *testing.T) {
 				{ID: 0, Value: "[UNK]", Offsets: strutils.ByteOffsets{Start: 0, End: 3}},
 			},
 		},
+		{
+			"日本語",
+			[]models.Token{
+				{ID: 12, Value: "日本", Offsets: strutils.ByteOffsets{Start: 0, End: 6}},
+				{ID: 13, Value: "##語", Offsets: strutils.ByteOffsets{Start: 6, End: 9}},
+			},
+		},
 		{
 			"veryverylongterm",
 			[]models.Token{
@@ -94,6 +103,12 @@ func TestWordPieceModelTokenize(t *testing.T) {
 				t.Fatal(err)
 			}
 			assertEqual(t, tokens, tc.expected)
+
+			count, err := wordPiece.CountTokens(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, count, len(tc.expected))
 		})
 	}
 }
@@ -8,8 +8,13 @@ import (
 	"fmt"
 	"github.com/nlpodyssey/gotokenizers/splitpattern"
 	"github.com/nlpodyssey/gotokenizers/strutils"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // NormalizedString takes care of processing an "original" string to modify
@@ -35,6 +40,8 @@ type NormalizedString struct {
 	// of the missing part, so that we can still give offsets from this
 	// original string.
 	originalShift int
+	// Whether the alignments are not tracked (see FromStringWithoutAlignments).
+	untracked bool
 }
 
 // AlignmentRange represents a (start, end) range for representing the
@@ -46,6 +53,16 @@ type AlignmentRange struct {
 	end int
 }
 
+// Start returns the start byte position, inclusive.
+func (a AlignmentRange) Start() int {
+	return a.start
+}
+
+// End returns the end byte position, exclusive.
+func (a AlignmentRange) End() int {
+	return a.end
+}
+
 // New returns a new NormalizedString.
 func New(
 	original string,
@@ -71,6 +88,25 @@ func FromString(s string) *NormalizedString {
 	}
 }
 
+// FromStringWithoutAlignments returns a new NormalizedString built from the
+// given string, which does not keep track of the alignments.
+//
+// This lightweight mode is useful when the offsets are not needed, since
+// it saves the memory and the time required to update an AlignmentRange for
+// each byte. The "original" string always mirrors the "normalized" one, so
+// ranges of both referentials simply refer to the "normalized" string.
+//
+// The mode is inherited by any NormalizedString obtained with Slice or Split.
+func FromStringWithoutAlignments(s string) *NormalizedString {
+	return &NormalizedString{
+		original:      s,
+		normalized:    s,
+		alignments:    nil,
+		originalShift: 0,
+		untracked:     true,
+	}
+}
+
 func alignmentsFromString(s string) []AlignmentRange {
 	alignments := make([]AlignmentRange, 0, len(s))
 
@@ -98,6 +134,27 @@ func (ns *NormalizedString) GetOriginal() string {
 	return ns.original
 }
 
+// Alignments returns a copy of the alignments of the NormalizedString, that
+// is the (start, end) range of the "original" string for each byte of the
+// "normalized" string.
+//
+// It returns nil if the alignments are not tracked.
+func (ns *NormalizedString) Alignments() []AlignmentRange {
+	if ns.untracked {
+		return nil
+	}
+	alignments := make([]AlignmentRange, len(ns.alignments))
+	copy(alignments, ns.alignments)
+	return alignments
+}
+
+// AlignmentsTracked reports whether the NormalizedString keeps track of
+// the alignments, that is, it was not created with
+// FromStringWithoutAlignments.
+func (ns *NormalizedString) AlignmentsTracked() bool {
+	return !ns.untracked
+}
+
 // Len returns the length in bytes of the "normalized" string.
 func (ns *NormalizedString) Len() int {
 	return len(ns.normalized)
@@ -159,12 +216,25 @@ func (ns *NormalizedString) TransformRange(
 		return
 	}
 
+	if ns.untracked {
+		ns.transformRangeUntracked(normalizedRange, dest)
+		return
+	}
+
 	// Retrieve the original characters that are being replaced. This lets us
 	// compute the change in byte sizes along the way.
 	replacedNormalizedString := ns.normalized[normalizedRange.Start():normalizedRange.End()]
 
-	initialRemoved := len(replacedNormalizedString[:initialOffset])
-	replacedNormalizedRunes := []rune(replacedNormalizedString[initialOffset:])
+	// The initialOffset is a number of runes: find the corresponding bytes
+	initialRemoved := len(replacedNormalizedString)
+	for i := range replacedNormalizedString {
+		if initialOffset == 0 {
+			initialRemoved = i
+			break
+		}
+		initialOffset--
+	}
+	replacedNormalizedRunes := []rune(replacedNormalizedString[initialRemoved:])
 
 	offset := initialRemoved + normalizedRange.Start()
 	alignments := make([]AlignmentRange, 0, normalizedRange.Len())
@@ -228,6 +298,20 @@ func (ns *NormalizedString) TransformRange(
 	)
 }
 
+// transformRangeUntracked is the lightweight version of TransformRange,
+// which only rebuilds the string, without tracking the alignments.
+func (ns *NormalizedString) transformRangeUntracked(rng NormalizedRange, dest []RuneChange) {
+	var b strings.Builder
+	b.Grow(len(ns.normalized) - rng.Len() + len(dest))
+	b.WriteString(ns.normalized[:rng.start])
+	for _, runeChange := range dest {
+		b.WriteRune(runeChange.Rune)
+	}
+	b.WriteString(ns.normalized[rng.end:])
+	ns.normalized = b.String()
+	ns.original = ns.normalized
+}
+
 // Transform applies transformations to the current normalized version of
 // the string, while updating the alignments.
 //
@@ -237,7 +321,13 @@ func (ns *NormalizedString) Transform(dest []RuneChange, initialOffset int) {
 }
 
 // OriginalAlignments recalculates the original alignments.
+//
+// It returns nil if the alignments are not tracked.
 func (ns *NormalizedString) OriginalAlignments() []AlignmentRange {
+	if ns.untracked {
+		return nil
+	}
+
 	// (start, end) are in alignments
 	// (offset, length) are in originalAlignments
 	originalAlignments := make([]AlignmentRange, 0, len(ns.original))
@@ -346,6 +436,14 @@ func (ns *NormalizedString) CoerceRangeToNormalized(r Range) (NormalizedRange, b
 		return nr, true
 	}
 
+	// Without alignments, both referentials are the same
+	if ns.untracked {
+		if r.Start() > r.End() || r.Start() < 0 || r.End() > len(ns.normalized) {
+			return NewNormalizedRange(0, 0), false
+		}
+		return NewNormalizedRange(r.Start(), r.End()), true
+	}
+
 	// If we target an empty range, let's return the same
 	if r.Len() == 0 {
 		return NewNormalizedRange(r.Start(), r.End()), true
@@ -405,13 +503,25 @@ func (ns *NormalizedString) CoerceRangeToOriginal(r Range) (OriginalRange, bool)
 		return or, true
 	}
 
-	// If we target an empty range, let's return the same
+	// Without alignments, both referentials are the same
+	if ns.untracked {
+		if r.Start() > r.End() || r.Start() < 0 || r.End() > len(ns.normalized) {
+			return NewOriginalRange(0, 0), false
+		}
+		return NewOriginalRange(r.Start(), r.End()), true
+	}
+
+	// If we target an empty range, let's return the same, as long as it
+	// is within the original string
 	if r.Len() == 0 {
+		if r.Start() < 0 || r.End() > ns.OriginalLen() {
+			return NewOriginalRange(0, 0), false
+		}
 		return NewOriginalRange(r.Start(), r.End()), true
 	}
 
-	// If the target goes reverse, return invalid status
-	if r.Start() > r.End() {
+	// If the target goes reverse, or out of bounds, return invalid status
+	if r.Start() > r.End() || r.Start() < 0 || r.End() > len(ns.alignments) {
 		return NewOriginalRange(0, 0), false
 	}
 
@@ -461,9 +571,7 @@ func (ns *NormalizedString) Append(s string) {
 
 	transformations := make([]RuneChange, 0, len(s)+1)
 
-	runes := []rune(ns.normalized)
-	lastRuneIndex := len(runes) - 1
-	lastRune := runes[lastRuneIndex]
+	lastRune, lastRuneSize := utf8.DecodeLastRuneInString(ns.normalized)
 
 	transformations = append(transformations, RuneChange{Rune: lastRune, Change: 0})
 
@@ -471,8 +579,7 @@ func (ns *NormalizedString) Append(s string) {
 		transformations = append(transformations, RuneChange{Rune: r, Change: 1})
 	}
 
-	// FIXME: it's probably wrong to pass the rune index, instead of byte index
-	ns.TransformRange(NewNormalizedRange(lastRuneIndex, ns.Len()), transformations, 0)
+	ns.TransformRange(NewNormalizedRange(ns.Len()-lastRuneSize, ns.Len()), transformations, 0)
 }
 
 // SplitDelimiterBehavior is used by NormalizedString.Split to define the
@@ -487,11 +594,11 @@ func (ns *NormalizedString) Append(s string) {
 type SplitDelimiterBehavior uint8
 
 const (
-	SplitDelimiterRemoved            SplitDelimiterBehavior = iota
-	SplitDelimiterIsolated                                  = iota
-	SplitDelimiterMergedWithPrevious                        = iota
-	SplitDelimiterMergedWithNext                            = iota
-	SplitDelimiterContiguous                                = iota
+	SplitDelimiterRemoved SplitDelimiterBehavior = iota
+	SplitDelimiterIsolated
+	SplitDelimiterMergedWithPrevious
+	SplitDelimiterMergedWithNext
+	SplitDelimiterContiguous
 )
 
 // Split splits the current string in many subparts.
@@ -646,6 +753,17 @@ func (ns *NormalizedString) Slice(rng Range) (*NormalizedString, bool) {
 	if !nrOk {
 		return nil, false
 	}
+
+	if ns.untracked {
+		sliced := ns.normalized[normalizedRange.start:normalizedRange.end]
+		return &NormalizedString{
+			original:      sliced,
+			normalized:    sliced,
+			originalShift: ns.originalShift + normalizedRange.start,
+			untracked:     true,
+		}, true
+	}
+
 	originalRange, orOk := ns.CoerceRangeToOriginal(rng)
 	if !orOk {
 		return nil, false
@@ -672,6 +790,17 @@ func (ns *NormalizedString) Slice(rng Range) (*NormalizedString, bool) {
 
 // Filter applies filtering over the characters of the NormalizedString.
 func (ns *NormalizedString) Filter(keep func(rune) bool) {
+	if ns.untracked {
+		ns.normalized = strings.Map(func(r rune) rune {
+			if keep(r) {
+				return r
+			}
+			return -1
+		}, ns.normalized)
+		ns.original = ns.normalized
+		return
+	}
+
 	removed := 0
 	removedStart := 0
 	transforms := make([]RuneChange, 0, ns.Len())
@@ -710,6 +839,12 @@ func (ns *NormalizedString) Filter(keep func(rune) bool) {
 
 // Map maps the characters of the NormalizedString.
 func (ns *NormalizedString) Map(mapFunc func(rune) rune) {
+	if ns.untracked {
+		ns.normalized = strings.Map(mapFunc, ns.normalized)
+		ns.original = ns.normalized
+		return
+	}
+
 	transforms := make([]RuneChange, 0, ns.Len())
 	for _, r := range ns.normalized {
 		transforms = append(transforms, RuneChange{
@@ -720,48 +855,222 @@ func (ns *NormalizedString) Map(mapFunc func(rune) rune) {
 	ns.Transform(transforms, 0)
 }
 
+// FlatMap replaces each rune of the NormalizedString with the string
+// returned by mapFunc, which can be empty to remove the rune, or contain
+// multiple runes.
+//
+// All the runes of the returned string are aligned to the replaced rune.
+// Invalid UTF-8 sequences in the returned string are replaced with the
+// Unicode replacement character (U+FFFD).
+func (ns *NormalizedString) FlatMap(mapFunc func(rune) string) {
+	var sb strings.Builder
+	sb.Grow(len(ns.normalized))
+
+	mapRune := func(r rune) string {
+		mapped := mapFunc(r)
+		if !utf8.ValidString(mapped) {
+			return strings.ToValidUTF8(mapped, string(utf8.RuneError))
+		}
+		return mapped
+	}
+
+	if ns.untracked {
+		for _, r := range ns.normalized {
+			sb.WriteString(mapRune(r))
+		}
+		ns.normalized = sb.String()
+		ns.original = ns.normalized
+		return
+	}
+
+	alignments := make([]AlignmentRange, 0, len(ns.alignments))
+	for i, r := range ns.normalized {
+		mapped := mapRune(r)
+		sb.WriteString(mapped)
+		for j := 0; j < len(mapped); j++ {
+			alignments = append(alignments, ns.alignments[i])
+		}
+	}
+	ns.normalized = sb.String()
+	ns.alignments = alignments
+}
+
+// NFD applies the Unicode canonical decomposition (Normalization Form D) to
+// the "normalized" string.
+//
+// The runes resulting from the decomposition of a character are aligned to
+// that character.
+//
+// Unlike norm.NFD.String, no combining grapheme joiner (U+034F) is inserted
+// in long sequences of non-starters.
+func (ns *NormalizedString) NFD() {
+	if norm.NFD.IsNormalString(ns.normalized) {
+		return
+	}
+	if ns.untracked {
+		var sb strings.Builder
+		for s := ns.normalized; len(s) > 0; {
+			end := norm.NFD.NextBoundaryInString(s, true)
+			sb.WriteString(norm.NFD.String(s[:end]))
+			s = s[end:]
+		}
+		ns.normalized = sb.String()
+		ns.original = ns.normalized
+		return
+	}
+
+	transforms := make([]RuneChange, 0, ns.Len())
+	for s := ns.normalized; len(s) > 0; {
+		// Each segment is a starter followed by non-starters, whose
+		// combining marks might be reordered by the decomposition.
+		end := norm.NFD.NextBoundaryInString(s, true)
+		transforms = appendNFDRuneChanges(transforms, s[:end])
+		s = s[end:]
+	}
+	ns.Transform(transforms, 0)
+}
+
+// appendNFDRuneChanges appends the RuneChanges of the NFD decomposition of
+// a segment.
+func appendNFDRuneChanges(transforms []RuneChange, segment string) []RuneChange {
+	decomposed := norm.NFD.String(segment)
+
+	// Decomposing each rune separately gives the most accurate alignments,
+	// unless the canonical ordering moves some combining marks.
+	start := len(transforms)
+	var sb strings.Builder
+	for _, r := range segment {
+		for i, dr := range norm.NFD.String(string(r)) {
+			change := 1
+			if i == 0 {
+				change = 0
+			}
+			transforms = append(transforms, RuneChange{Rune: dr, Change: change})
+			sb.WriteRune(dr)
+		}
+	}
+	if sb.String() == decomposed {
+		return transforms
+	}
+
+	transforms = transforms[:start]
+	segmentLen := utf8.RuneCountInString(segment)
+	for i, dr := range []rune(decomposed) {
+		change := 1
+		if i < segmentLen {
+			change = 0
+		}
+		transforms = append(transforms, RuneChange{Rune: dr, Change: change})
+	}
+	return transforms
+}
+
 // ToUpper remaps all Unicode letters of the "normalized" string to their
-// upper case.
-// FIXME: Go `unicode` package does not consider Unicode special casing
-//        (see https://www.unicode.org/Public/UCD/latest/ucd/SpecialCasing.txt)
-//        As a result, every single rune is always transformed to a single
-//        new rune. This is not the case in the original rust implementation.
-//        Should we consider using another "better" package?
-//        Explanatory example:
-//        ```rust
-//        fn main() {
-//            let a = '\u{00DF}';
-//            println!("{} {:?}", a, a.to_string().chars().count());
-//            // => ß 1
-//            let b = a.to_uppercase();
-//            println!("{} {}", b, b.to_string().chars().count());
-//            // => SS 2
-//        }
-//        ```
-//        ```go
-//        package main
-//        import (
-// 	          "fmt"
-// 	          "unicode"
-//        )
-//        func main() {
-// 	          a := '\u00DF'
-// 	          fmt.Println(string(a), len([]rune(string(a))))
-//            // => ß 1
-// 	          b := unicode.ToUpper(a)
-// 	          fmt.Println(string(b), len([]rune(string(b))))
-//            // => ß 1
-//        }
-//        ```
+// upper case, including the unconditional mappings from Unicode special
+// casing, where a single rune can become multiple ones (for example, "ß"
+// becomes "SS").
 func (ns *NormalizedString) ToUpper() {
-	ns.Map(unicode.ToUpper)
+	ns.ToUpperLanguage(language.Und)
 }
 
 // ToLower remaps all Unicode letters of the "normalized" string to their
-// lower case.
-// FIXME: see Unicode special casing notes on `NormalizedString.ToUpper()`
+// lower case, including the unconditional mappings from Unicode special
+// casing, where a single rune can become multiple ones (for example, "İ"
+// becomes "i̇").
 func (ns *NormalizedString) ToLower() {
-	ns.Map(unicode.ToLower)
+	ns.ToLowerLanguage(language.Und)
+}
+
+// ToUpperLanguage is like ToUpper, also applying the language-specific
+// rules for the given language, such as the Turkish dotted capital "İ"
+// for "i".
+//
+// Each rune is mapped independently, so the rules which depend on the
+// context (such as the Greek final sigma) are not applied.
+func (ns *NormalizedString) ToUpperLanguage(tag language.Tag) {
+	ns.mapCase(cases.Upper(tag), unicode.ToUpper, !hasASCIISpecialCasing(tag))
+}
+
+// ToLowerLanguage is like ToLower, also applying the language-specific
+// rules for the given language, such as the Turkish dotless "ı" for "I".
+//
+// Each rune is mapped independently, so the rules which depend on the
+// context (such as the Greek final sigma) are not applied.
+func (ns *NormalizedString) ToLowerLanguage(tag language.Tag) {
+	ns.mapCase(cases.Lower(tag), unicode.ToLower, !hasASCIISpecialCasing(tag))
+}
+
+// hasASCIISpecialCasing reports whether the case mappings of the given
+// language differ from the simple ones for some ASCII letters, as the
+// Turkish and Azerbaijani "i" and "I" do.
+func hasASCIISpecialCasing(tag language.Tag) bool {
+	base, _ := tag.Base()
+	switch base.String() {
+	case "tr", "az":
+		return true
+	default:
+		return false
+	}
+}
+
+// mapCase maps each rune of the normalized string with the given Caser.
+// If asciiFastPath is true, ASCII runes are mapped with the simple mapping
+// function instead, which must give the same result.
+func (ns *NormalizedString) mapCase(caser cases.Caser, simple func(rune) rune, asciiFastPath bool) {
+	// A single rune is mapped to at most three runes by Unicode special
+	// casing, so the buffers are reused for all the runes.
+	var src [utf8.UTFMax]byte
+	var dst [4 * utf8.UTFMax]byte
+
+	// mapRune returns the mapping of r, which is only valid until the
+	// next call.
+	mapRune := func(r rune) []byte {
+		n := utf8.EncodeRune(src[:], r)
+		caser.Reset()
+		nDst, _, err := caser.Transform(dst[:], src[:n], true)
+		if err == transform.ErrShortDst {
+			return []byte(caser.String(string(r)))
+		}
+		if err != nil || nDst == 0 {
+			// Runes are never removed, to keep the alignments consistent
+			return src[:n]
+		}
+		return dst[:nDst]
+	}
+
+	if ns.untracked {
+		var sb strings.Builder
+		sb.Grow(len(ns.normalized))
+		for _, r := range ns.normalized {
+			if asciiFastPath && r < utf8.RuneSelf {
+				sb.WriteByte(byte(simple(r)))
+			} else {
+				sb.Write(mapRune(r))
+			}
+		}
+		ns.normalized = sb.String()
+		ns.original = ns.normalized
+		return
+	}
+
+	transforms := make([]RuneChange, 0, ns.Len())
+	for _, r := range ns.normalized {
+		if asciiFastPath && r < utf8.RuneSelf {
+			transforms = append(transforms, RuneChange{Rune: simple(r), Change: 0})
+			continue
+		}
+		mapped := mapRune(r)
+		for i := 0; len(mapped) > 0; i++ {
+			mr, size := utf8.DecodeRune(mapped)
+			mapped = mapped[size:]
+			change := 1
+			if i == 0 {
+				change = 0
+			}
+			transforms = append(transforms, RuneChange{Rune: mr, Change: change})
+		}
+	}
+	ns.Transform(transforms, 0)
 }
 
 // Trim removes leading and trailing spaces from the "normalized" string.
@@ -0,0 +1,292 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package normalizedstring
+
+import (
+	"github.com/nlpodyssey/gotokenizers/splitpattern"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxFuzzInputLen is the maximum length in bytes of the fuzzed input.
+	maxFuzzInputLen = 64
+	// maxFuzzOps is the maximum number of operations applied to the input.
+	maxFuzzOps = 16
+	// maxFuzzLen is the length in bytes of the normalized string beyond
+	// which the operations which can expand it are no longer applied.
+	maxFuzzLen = 256
+)
+
+// FuzzNormalizedString applies a sequence of operations, driven by the ops
+// bytes, to a NormalizedString built from the input, checking the alignments
+// invariants after each step. The same operations are applied to a
+// NormalizedString without alignments, which must produce the same
+// normalized strings.
+//
+// The input length, the number of operations, and the growth of the string
+// are limited, to keep both each execution and the minimization of the
+// interesting inputs fast.
+func FuzzNormalizedString(f *testing.F) {
+	f.Add("Hello world", []byte{0, 1, 2, 3})
+	f.Add(" Héllo  wörld! ", []byte{4, 0, 5, 1, 6, 2, 7, 3})
+	f.Add("　a　b　", []byte{8, 0, 9, 0})
+	f.Add("こんにちは-世界--!", []byte{2, 4, 3, 1, 5, 2})
+	f.Add("ǅ İ ß ﬃ", []byte{0, 0, 1, 0, 6, 1, 7, 2})
+	f.Add("Ǻ ệ a\u0301\u0323 한", []byte{10, 0, 5, 0x31, 10, 0, 0, 1})
+	f.Add("", []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	f.Add("ﬁne 😀 x", []byte{11, 0, 5, 0x12, 11, 3, 9, 1})
+
+	f.Fuzz(func(t *testing.T, input string, ops []byte) {
+		// The arguments are truncated before anything else, so that the bytes
+		// beyond the limits never affect the coverage
+		input = truncateFuzzInput(input)
+		if len(ops) > 2*maxFuzzOps {
+			ops = ops[:2*maxFuzzOps]
+		}
+		if !utf8.ValidString(input) {
+			t.Skip()
+		}
+		ns := FromString(input)
+		checkInvariants(t, ns, "FromString")
+		untracked := FromStringWithoutAlignments(input)
+
+		for i := 0; i+1 < len(ops); i += 2 {
+			op, arg := ops[i], ops[i+1]
+			if isExpandingFuzzOp(op) && ns.Len() > maxFuzzLen {
+				continue
+			}
+			name := applyFuzzOp(t, ns, op, arg)
+			if name == "" {
+				continue
+			}
+			checkInvariants(t, ns, name)
+			applyFuzzOp(t, untracked, op, arg)
+			checkUntracked(t, untracked, ns, name)
+
+			if op%12 == 5 || op%12 == 6 {
+				// Continue with one of the resulting sub-strings
+				sub := fuzzSubString(t, ns, op, arg)
+				untrackedSub := fuzzSubString(t, untracked, op, arg)
+				if sub != nil && untrackedSub == nil {
+					t.Fatalf("%s: tracked result %v, untracked result nil", name, sub)
+				}
+				// The untracked result is ignored when the tracked operation
+				// fails, such as when slicing an empty range
+				if sub != nil {
+					ns, untracked = sub, untrackedSub
+					checkInvariants(t, ns, name+" result")
+					checkUntracked(t, untracked, ns, name+" result")
+				}
+			}
+		}
+	})
+}
+
+// truncateFuzzInput truncates s to at most maxFuzzInputLen bytes, on a rune
+// boundary.
+func truncateFuzzInput(s string) string {
+	if len(s) <= maxFuzzInputLen {
+		return s
+	}
+	n := maxFuzzInputLen
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// isExpandingFuzzOp reports whether the operation applied by applyFuzzOp
+// can make the normalized string longer.
+func isExpandingFuzzOp(op byte) bool {
+	switch op % 12 {
+	case 0, 5, 6, 8:
+		return false
+	default:
+		return true
+	}
+}
+
+func applyFuzzOp(t *testing.T, ns *NormalizedString, op, arg byte) string {
+	switch op % 12 {
+	case 0:
+		ns.Filter(func(r rune) bool { return (int(r)+int(arg))%3 != 0 })
+		return "Filter"
+	case 1:
+		ns.Map(func(r rune) rune {
+			if (int(r)+int(arg))%2 == 0 {
+				return 'ß'
+			}
+			return unicode.ToUpper(r)
+		})
+		return "Map"
+	case 2:
+		ns.ToLower()
+		return "ToLower"
+	case 3:
+		err := ns.Replace(splitpattern.FromRune(fuzzRune(ns, arg)), string([]rune{'a', '€', '😀'}[:arg%4]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Replace"
+	case 4:
+		ns.Prepend(string([]rune{'▁', ' ', 'x'}[:arg%3+1]))
+		return "Prepend"
+	case 5, 6:
+		// See fuzzSubString
+		return "Slice/Split"
+	case 7:
+		ns.Append(string([]rune{'▁', ' ', 'x'}[:arg%3+1]))
+		return "Append"
+	case 8:
+		ns.TrimLeftRight(arg%2 == 0, arg%3 == 0)
+		return "TrimLeftRight"
+	case 9:
+		runes := []rune(ns.Get())
+		if len(runes) == 0 {
+			return ""
+		}
+		start := runeByteIndex(ns.Get(), int(arg)%len(runes))
+		end := runeByteIndex(ns.Get(), int(arg)%len(runes)+1)
+		ns.TransformRange(NewNormalizedRange(start, end), []RuneChange{
+			{Rune: 'Ω', Change: 0},
+			{Rune: 'x', Change: 1},
+		}, 0)
+		return "TransformRange"
+	case 10:
+		ns.NFD()
+		return "NFD"
+	case 11:
+		ns.FlatMap(func(r rune) string {
+			switch (int(r) + int(arg)) % 4 {
+			case 0:
+				return ""
+			case 1:
+				// At most one extra rune, so that repeated FlatMaps
+				// do not grow the string exponentially
+				return string([]rune{r, 'é'})
+			default:
+				return string(r)
+			}
+		})
+		return "FlatMap"
+	}
+	return ""
+}
+
+func fuzzSubString(t *testing.T, ns *NormalizedString, op, arg byte) *NormalizedString {
+	if op%12 == 5 {
+		runes := []rune(ns.Get())
+		if len(runes) == 0 {
+			return nil
+		}
+		a := int(arg) % (len(runes) + 1)
+		b := int(arg/16) % (len(runes) + 1)
+		if a > b {
+			a, b = b, a
+		}
+		rng := NewNormalizedRange(runeByteIndex(ns.Get(), a), runeByteIndex(ns.Get(), b))
+		sliced, ok := ns.Slice(rng)
+		if !ok && rng.Len() == 0 {
+			// Empty ranges are kept as they are in the original referential,
+			// so they might be out of bounds
+			return nil
+		}
+		if !ok {
+			t.Fatalf("Slice(%v) of %q failed", rng, ns.Get())
+		}
+		return sliced
+	}
+
+	behavior := SplitDelimiterBehavior(arg % 5)
+	splits, err := ns.Split(splitpattern.FromRune(fuzzRune(ns, arg)), behavior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, split := range splits {
+		if split.AlignmentsTracked() {
+			checkInvariants(t, split, "Split")
+		}
+	}
+	if len(splits) == 0 {
+		return nil
+	}
+	return splits[int(arg)%len(splits)]
+}
+
+// fuzzRune picks a rune of the normalized string, or a fixed rune if
+// the string is empty.
+func fuzzRune(ns *NormalizedString, arg byte) rune {
+	runes := []rune(ns.Get())
+	if len(runes) == 0 {
+		return ' '
+	}
+	return runes[int(arg)%len(runes)]
+}
+
+// runeByteIndex returns the byte index of the n-th rune of s, or len(s).
+func runeByteIndex(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+func checkInvariants(t *testing.T, ns *NormalizedString, step string) {
+	t.Helper()
+
+	if !utf8.ValidString(ns.Get()) {
+		t.Fatalf("%s: invalid UTF-8 normalized string %q", step, ns.Get())
+	}
+	if len(ns.alignments) != ns.Len() {
+		t.Fatalf("%s: %d alignments for normalized string %q (%d bytes)",
+			step, len(ns.alignments), ns.Get(), ns.Len())
+	}
+
+	original := ns.GetOriginal()
+	for i, a := range ns.alignments {
+		if a.start < 0 || a.start > a.end || a.end > len(original) {
+			t.Fatalf("%s: alignment %d %v out of bounds of original %q", step, i, a, original)
+		}
+		if i > 0 {
+			prev := ns.alignments[i-1]
+			if a.start < prev.start || a.end < prev.end {
+				t.Fatalf("%s: alignments %d %v and %d %v are not monotonic", step, i-1, prev, i, a)
+			}
+		}
+	}
+
+	normalized := ns.Get()
+	for start, r := range normalized {
+		end := start + utf8.RuneLen(r)
+		or, ok := ns.CoerceRangeToOriginal(NewNormalizedRange(start, end))
+		if !ok {
+			t.Fatalf("%s: cannot coerce normalized range [%d, %d) of %q", step, start, end, normalized)
+		}
+		if !strutils.IsRuneBoundary(original, or.Start()) || !strutils.IsRuneBoundary(original, or.End()) {
+			t.Fatalf("%s: normalized range [%d, %d) of %q coerced to original range %v of %q, not on rune boundaries",
+				step, start, end, normalized, or, original)
+		}
+	}
+}
+
+func checkUntracked(t *testing.T, untracked, tracked *NormalizedString, step string) {
+	t.Helper()
+
+	if untracked.AlignmentsTracked() || untracked.Alignments() != nil {
+		t.Fatalf("%s: untracked NormalizedString has alignments", step)
+	}
+	if untracked.Get() != tracked.Get() {
+		t.Fatalf("%s: untracked normalized string %q, tracked %q", step, untracked.Get(), tracked.Get())
+	}
+	if untracked.GetOriginal() != untracked.Get() {
+		t.Fatalf("%s: untracked original %q differs from normalized %q", step, untracked.GetOriginal(), untracked.Get())
+	}
+}
@@ -7,10 +7,14 @@ package normalizedstring
 import (
 	"github.com/nlpodyssey/gotokenizers/splitpattern"
 	"github.com/nlpodyssey/gotokenizers/strutils"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 	"unicode"
+	"unicode/utf8"
 )
 
 func TestNew(t *testing.T) {
@@ -104,6 +108,42 @@ func TestFromString(t *testing.T) {
 	))
 }
 
+func TestFromStringWithoutAlignments(t *testing.T) {
+	t.Parallel()
+
+	ns := FromStringWithoutAlignments("  Héllo, World!  ")
+	assertEqual(t, ns.AlignmentsTracked(), false)
+	assertEqual(t, FromString("Foo").AlignmentsTracked(), true)
+
+	ns.Trim()
+	ns.Filter(func(r rune) bool { return r != ',' })
+	ns.ToLower()
+	ns.Prepend("▁")
+	assertEqual(t, ns.Get(), "▁héllo world!")
+	assertEqual(t, ns.GetOriginal(), "▁héllo world!")
+	assertEqual(t, ns.Alignments(), []AlignmentRange(nil))
+	assertEqual(t, ns.OriginalAlignments(), []AlignmentRange(nil))
+
+	splits, err := ns.Split(splitpattern.FromRune(' '), SplitDelimiterRemoved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, len(splits), 2)
+	assertEqual(t, splits[0].Get(), "▁héllo")
+	assertEqual(t, splits[0].OriginalOffsets(), strutils.ByteOffsets{Start: 0, End: 9})
+	assertEqual(t, splits[1].Get(), "world!")
+	assertEqual(t, splits[1].OriginalOffsets(), strutils.ByteOffsets{Start: 10, End: 16})
+	assertEqual(t, splits[1].AlignmentsTracked(), false)
+
+	sliced, ok := splits[1].Slice(NewOriginalRange(1, 3))
+	assertEqual(t, ok, true)
+	assertEqual(t, sliced.Get(), "or")
+	assertEqual(t, sliced.OriginalOffsets(), strutils.ByteOffsets{Start: 11, End: 13})
+
+	_, ok = splits[1].Slice(NewNormalizedRange(2, 7))
+	assertEqual(t, ok, false)
+}
+
 func TestNormalizedStringGet(t *testing.T) {
 	t.Parallel()
 
@@ -128,6 +168,20 @@ func TestNormalizedStringGetOriginal(t *testing.T) {
 	assertEqual(t, ns.GetOriginal(), "Foo")
 }
 
+func TestNormalizedStringAlignments(t *testing.T) {
+	t.Parallel()
+
+	ns := FromString("aßz")
+	alignments := ns.Alignments()
+	assertEqual(t, alignments, []AlignmentRange{{0, 1}, {1, 3}, {1, 3}, {3, 4}})
+	assertEqual(t, alignments[1].Start(), 1)
+	assertEqual(t, alignments[1].End(), 3)
+
+	// The returned slice is a copy
+	alignments[0] = AlignmentRange{42, 43}
+	assertEqual(t, ns.Alignments()[0], AlignmentRange{0, 1})
+}
+
 func TestNormalizedStringLen(t *testing.T) {
 	t.Parallel()
 
@@ -336,6 +390,342 @@ func TestNormalizedStringFilter(t *testing.T) {
 	})
 }
 
+func TestNormalizedStringFlatMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Expand and remove runes", func(t *testing.T) {
+		t.Parallel()
+
+		ns := FromString("\u200bﬁ\u200bx")
+		ns.FlatMap(func(r rune) string {
+			switch r {
+			case 'ﬁ':
+				return "fi"
+			case '\u200b':
+				return ""
+			default:
+				return string(r)
+			}
+		})
+		assertEqual(t, ns, New(
+			"\u200bﬁ\u200bx",
+			"fix",
+			[]AlignmentRange{
+				{3, 6},
+				{3, 6},
+				{9, 10},
+			},
+			0,
+		))
+		assertEqual(t, ns.OriginalAlignments(), []AlignmentRange{
+			{0, 0},
+			{0, 0},
+			{0, 0},
+			{0, 2},
+			{0, 2},
+			{0, 2},
+			{2, 2},
+			{2, 2},
+			{2, 2},
+			{2, 3},
+		})
+	})
+
+	t.Run("Same as Map and Filter", func(t *testing.T) {
+		t.Parallel()
+
+		input := " Héllo,  wörld! "
+		expected := FromString(input)
+		expected.Map(unicode.ToUpper)
+		expected.Filter(func(r rune) bool { return !unicode.IsSpace(r) })
+
+		actual := FromString(input)
+		actual.FlatMap(func(r rune) string {
+			if unicode.IsSpace(r) {
+				return ""
+			}
+			return string(unicode.ToUpper(r))
+		})
+		assertEqual(t, actual, expected)
+	})
+
+	t.Run("Without alignments", func(t *testing.T) {
+		t.Parallel()
+
+		ns := FromStringWithoutAlignments("ﬁ😀")
+		ns.FlatMap(func(r rune) string {
+			if r == '😀' {
+				return ":smile:"
+			}
+			return "fi"
+		})
+		assertEqual(t, ns.Get(), "fi:smile:")
+		assertEqual(t, ns.GetOriginal(), "fi:smile:")
+	})
+
+	t.Run("Invalid UTF-8", func(t *testing.T) {
+		t.Parallel()
+
+		mapFunc := func(r rune) string {
+			if r == 'b' {
+				return "\xff\xfe!"
+			}
+			return string(r)
+		}
+
+		ns := FromString("ab")
+		ns.FlatMap(mapFunc)
+		assertEqual(t, ns, New(
+			"ab",
+			"a\uFFFD!",
+			[]AlignmentRange{
+				{0, 1},
+				{1, 2},
+				{1, 2},
+				{1, 2},
+				{1, 2},
+			},
+			0,
+		))
+
+		ns = FromStringWithoutAlignments("ab")
+		ns.FlatMap(mapFunc)
+		assertEqual(t, ns.Get(), "a\uFFFD!")
+	})
+}
+
+func TestNormalizedStringNFD(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Precomposed characters", func(t *testing.T) {
+		t.Parallel()
+
+		ns := FromString("é!")
+		ns.NFD()
+		assertEqual(t, ns, New(
+			"é!",
+			"e\u0301!",
+			[]AlignmentRange{
+				{0, 2},
+				{0, 2},
+				{0, 2},
+				{2, 3},
+			},
+			0,
+		))
+	})
+
+	t.Run("Reordered combining marks", func(t *testing.T) {
+		t.Parallel()
+
+		// U+0323 must come before U+0301, since its canonical combining
+		// class is lower: the runes of the reordered segment are aligned
+		// by position
+		ns := FromString("xa\u0301\u0323")
+		ns.NFD()
+		assertEqual(t, ns.Get(), "xa\u0323\u0301")
+		assertEqual(t, ns.Alignments(), []AlignmentRange{
+			{0, 1},
+			{1, 2},
+			{2, 4},
+			{2, 4},
+			{4, 6},
+			{4, 6},
+		})
+	})
+
+	t.Run("Already decomposed", func(t *testing.T) {
+		t.Parallel()
+
+		ns := FromString("e\u0301")
+		ns.NFD()
+		assertEqual(t, ns, FromString("e\u0301"))
+	})
+
+	t.Run("Without alignments", func(t *testing.T) {
+		t.Parallel()
+
+		ns := FromStringWithoutAlignments("Café")
+		ns.NFD()
+		assertEqual(t, ns.Get(), "Cafe\u0301")
+		assertEqual(t, ns.GetOriginal(), "Cafe\u0301")
+	})
+
+	t.Run("Long sequence of non-starters", func(t *testing.T) {
+		t.Parallel()
+
+		input := "e" + strings.Repeat("\u0301", 40) + "é"
+		expected := "e" + strings.Repeat("\u0301", 40) + "e\u0301"
+
+		ns := FromString(input)
+		ns.NFD()
+		assertEqual(t, ns.Get(), expected)
+
+		ns = FromStringWithoutAlignments(input)
+		ns.NFD()
+		assertEqual(t, ns.Get(), expected)
+	})
+}
+
+func TestNormalizedStringToUpper(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Special casing", func(t *testing.T) {
+		t.Parallel()
+
+		ns := FromString("aßﬁ")
+		ns.ToUpper()
+		assertEqual(t, ns, New(
+			"aßﬁ",
+			"ASSFI",
+			[]AlignmentRange{
+				{0, 1},
+				{1, 3},
+				{1, 3},
+				{3, 6},
+				{3, 6},
+			},
+			0,
+		))
+	})
+
+	t.Run("Language", func(t *testing.T) {
+		t.Parallel()
+
+		ns := FromString("istanbul")
+		ns.ToUpperLanguage(language.Turkish)
+		assertEqual(t, ns.Get(), "İSTANBUL")
+		assertEqual(t, ns.Alignments()[:3], []AlignmentRange{{0, 1}, {0, 1}, {1, 2}})
+	})
+
+	t.Run("Without alignments", func(t *testing.T) {
+		t.Parallel()
+
+		ns := FromStringWithoutAlignments("straße")
+		ns.ToUpper()
+		assertEqual(t, ns.Get(), "STRASSE")
+		assertEqual(t, ns.GetOriginal(), "STRASSE")
+	})
+}
+
+func TestNormalizedStringToLower(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Special casing", func(t *testing.T) {
+		t.Parallel()
+
+		ns := FromString("İA")
+		ns.ToLower()
+		assertEqual(t, ns, New(
+			"İA",
+			"i\u0307a",
+			[]AlignmentRange{
+				{0, 2},
+				{0, 2},
+				{0, 2},
+				{2, 3},
+			},
+			0,
+		))
+	})
+
+	t.Run("Language", func(t *testing.T) {
+		t.Parallel()
+
+		ns := FromString("DİYARBAKIR")
+		ns.ToLowerLanguage(language.Turkish)
+		assertEqual(t, ns.Get(), "diyarbakır")
+
+		ns = FromString("DİYARBAKIR")
+		ns.ToLowerLanguage(language.English)
+		assertEqual(t, ns.Get(), "di\u0307yarbakir")
+	})
+
+	t.Run("Without alignments", func(t *testing.T) {
+		t.Parallel()
+
+		ns := FromStringWithoutAlignments("İSTANBUL")
+		ns.ToLowerLanguage(language.Turkish)
+		assertEqual(t, ns.Get(), "istanbul")
+	})
+}
+
+func TestNormalizedStringCaseMappingMatchesCaser(t *testing.T) {
+	t.Parallel()
+
+	var sb strings.Builder
+	for r := rune(0); r < utf8.RuneSelf; r++ {
+		sb.WriteRune(r)
+	}
+	sb.WriteString("ßİıŉΐǰΣσς ﬀ Ωmega DİYARBAKIR Ì")
+	input := sb.String()
+
+	tags := []language.Tag{
+		language.Und, language.English, language.Turkish,
+		language.Azerbaijani, language.Lithuanian, language.Dutch,
+		language.Greek, language.German,
+	}
+	for _, tag := range tags {
+		tag := tag
+		mappings := []struct {
+			name  string
+			caser cases.Caser
+			apply func(*NormalizedString)
+		}{
+			{"lower", cases.Lower(tag), func(ns *NormalizedString) { ns.ToLowerLanguage(tag) }},
+			{"upper", cases.Upper(tag), func(ns *NormalizedString) { ns.ToUpperLanguage(tag) }},
+		}
+		for _, m := range mappings {
+			// Each rune is expected to be mapped independently
+			var expected strings.Builder
+			for _, r := range input {
+				expected.WriteString(m.caser.String(string(r)))
+			}
+
+			ns := FromString(input)
+			m.apply(ns)
+			if ns.Get() != expected.String() {
+				t.Errorf("%s %v: expected %q, actual %q", m.name, tag, expected.String(), ns.Get())
+			}
+
+			ns = FromStringWithoutAlignments(input)
+			m.apply(ns)
+			if ns.Get() != expected.String() {
+				t.Errorf("%s %v without alignments: expected %q, actual %q", m.name, tag, expected.String(), ns.Get())
+			}
+		}
+	}
+}
+
+func BenchmarkNormalizedStringToLower(b *testing.B) {
+	input := strings.Repeat("The Quick Brown Fox Jumps Over The Lazy Dog. Ünïcödé İSTANBUL ", 64)
+
+	b.Run("ToLower", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			FromString(input).ToLower()
+		}
+	})
+	b.Run("Map unicode.ToLower", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			FromString(input).Map(unicode.ToLower)
+		}
+	})
+	b.Run("ToLower without alignments", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			FromStringWithoutAlignments(input).ToLower()
+		}
+	})
+	b.Run("strings.ToLower", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = strings.ToLower(input)
+		}
+	})
+}
+
 func Test_RangeConversion(t *testing.T) {
 	t.Parallel()
 
@@ -142,10 +142,10 @@ func (sn *BertNormalizer) handleChineseChars(ns *normalizedstring.NormalizedStri
 	ns.Transform(runeChanges, 0)
 }
 
-// stripAccents removes accent characters (Mn: Mark, non-spacing) from the
-// normalized string.
+// stripAccents decomposes the characters of the normalized string (NFD),
+// and removes the resulting accent characters (Mn: Mark, non-spacing).
 func (sn *BertNormalizer) stripAccents(ns *normalizedstring.NormalizedString) {
-	// TODO: ns.Nfd()
+	ns.NFD()
 	ns.Filter(func(r rune) bool {
 		return !unicode.In(r, unicode.Mn)
 	})
@@ -112,3 +112,24 @@ func TestBertNormalizerWithLowerCaseOnly(t *testing.T) {
 		t.Errorf("expected %#v, actual %#v", expected, actual)
 	}
 }
+
+func TestBertNormalizerStripsPrecomposedAccents(t *testing.T) {
+	t.Parallel()
+
+	sn := NewBertNormalizer(false, false, true, true)
+	ns := normalizedstring.FromString("Café Ñandú Ångström")
+	err := sn.Normalize(ns)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "cafe nandu angstrom"
+	if actual := ns.Get(); actual != expected {
+		t.Errorf("expected %#v, actual %#v", expected, actual)
+	}
+
+	// "e" is aligned to the original "é"
+	original, ok := ns.GetOriginalRange(normalizedstring.NewNormalizedRange(3, 4))
+	if !ok || original != "é" {
+		t.Errorf("expected (\"é\", true), actual (%#v, %t)", original, ok)
+	}
+}
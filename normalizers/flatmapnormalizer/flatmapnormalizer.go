@@ -0,0 +1,47 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flatmapnormalizer
+
+import (
+	"github.com/nlpodyssey/gotokenizers/normalizedstring"
+	"github.com/nlpodyssey/gotokenizers/normalizers"
+)
+
+// FlatMapNormalizer allows custom string normalizations, replacing each
+// rune with an arbitrary string, which can be empty to remove the rune, or
+// contain multiple runes (for example, to expand ligatures, or to replace
+// emojis with a textual description).
+//
+// The alignments are updated accordingly, via NormalizedString.FlatMap.
+type FlatMapNormalizer struct {
+	mapFunc func(rune) string
+}
+
+var _ normalizers.Normalizer = &FlatMapNormalizer{}
+
+// NewFlatMapNormalizer returns a new FlatMapNormalizer, replacing each rune
+// with the string returned by mapFunc. Invalid UTF-8 sequences in the
+// returned strings are replaced with U+FFFD.
+func NewFlatMapNormalizer(mapFunc func(rune) string) *FlatMapNormalizer {
+	return &FlatMapNormalizer{mapFunc: mapFunc}
+}
+
+// NewFlatMapNormalizerFromMap returns a new FlatMapNormalizer, replacing
+// the runes found in the given map with the associated strings. All other
+// runes are left unchanged.
+func NewFlatMapNormalizerFromMap(replacements map[rune]string) *FlatMapNormalizer {
+	return NewFlatMapNormalizer(func(r rune) string {
+		if s, ok := replacements[r]; ok {
+			return s
+		}
+		return string(r)
+	})
+}
+
+// Normalize maps the runes of the NormalizedString in place.
+func (fn *FlatMapNormalizer) Normalize(ns *normalizedstring.NormalizedString) error {
+	ns.FlatMap(fn.mapFunc)
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package flatmapnormalizer
+
+import (
+	"github.com/nlpodyssey/gotokenizers/normalizedstring"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestFlatMapNormalizer(t *testing.T) {
+	t.Parallel()
+
+	digits := []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+	sn := NewFlatMapNormalizer(func(r rune) string {
+		switch {
+		case unicode.IsDigit(r) && r <= '9':
+			return " " + digits[r-'0'] + " "
+		case unicode.Is(unicode.Cf, r):
+			return ""
+		default:
+			return string(r)
+		}
+	})
+	ns := normalizedstring.FromString("4\u200bx2")
+	err := sn.Normalize(ns)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := " four x two "
+	if actual := ns.Get(); actual != expected {
+		t.Errorf("expected %#v, actual %#v", expected, actual)
+	}
+
+	start := strings.Index(expected, "two")
+	original, ok := ns.GetOriginalRange(normalizedstring.NewNormalizedRange(start, start+3))
+	if !ok || original != "2" {
+		t.Errorf("expected (\"2\", true), actual (%#v, %t)", original, ok)
+	}
+}
+
+func TestFlatMapNormalizerFromMap(t *testing.T) {
+	t.Parallel()
+
+	sn := NewFlatMapNormalizerFromMap(map[rune]string{
+		'ﬁ':      "fi",
+		'😄':      ":smile:",
+		'\u00ad': "",
+	})
+	ns := normalizedstring.FromString("ﬁne\u00ad 😄")
+	err := sn.Normalize(ns)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "fine :smile:"
+	if actual := ns.Get(); actual != expected {
+		t.Errorf("expected %#v, actual %#v", expected, actual)
+	}
+}
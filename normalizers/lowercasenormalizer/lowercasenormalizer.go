@@ -7,21 +7,32 @@ package lowercasenormalizer
 import (
 	"github.com/nlpodyssey/gotokenizers/normalizedstring"
 	"github.com/nlpodyssey/gotokenizers/normalizers"
+	"golang.org/x/text/language"
 )
 
 // LowerCaseNormalizer allows string normalization remapping all Unicode
 // letters to their lower case.
-type LowerCaseNormalizer struct{}
+type LowerCaseNormalizer struct {
+	// The language whose specific casing rules are applied.
+	language language.Tag
+}
 
 var _ normalizers.Normalizer = &LowerCaseNormalizer{}
 
 // NewLowerCaseNormalizer returns a new LowerCaseNormalizer.
 func NewLowerCaseNormalizer() *LowerCaseNormalizer {
-	return &LowerCaseNormalizer{}
+	return &LowerCaseNormalizer{language: language.Und}
+}
+
+// NewLowerCaseNormalizerForLanguage returns a new LowerCaseNormalizer, which
+// also applies the casing rules specific to the given language (for example,
+// language.Turkish maps "I" to the dotless "ı").
+func NewLowerCaseNormalizerForLanguage(tag language.Tag) *LowerCaseNormalizer {
+	return &LowerCaseNormalizer{language: tag}
 }
 
 // Normalize transform the NormalizedString to lowercase in place.
 func (sn *LowerCaseNormalizer) Normalize(ns *normalizedstring.NormalizedString) error {
-	ns.ToLower()
+	ns.ToLowerLanguage(sn.language)
 	return nil
 }
@@ -6,6 +6,7 @@ package lowercasenormalizer
 
 import (
 	"github.com/nlpodyssey/gotokenizers/normalizedstring"
+	"golang.org/x/text/language"
 	"testing"
 )
 
@@ -23,3 +24,18 @@ func TestLowerCaseNormalizer(t *testing.T) {
 		t.Errorf("expected %#v, actual %#v", expected, actual)
 	}
 }
+
+func TestLowerCaseNormalizerForLanguage(t *testing.T) {
+	t.Parallel()
+
+	sn := NewLowerCaseNormalizerForLanguage(language.Turkish)
+	ns := normalizedstring.FromString("IŞIK İzmir")
+	err := sn.Normalize(ns)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := "ışık izmir"
+	if actual := ns.Get(); actual != expected {
+		t.Errorf("expected %#v, actual %#v", expected, actual)
+	}
+}
@@ -21,6 +21,11 @@ func NewSequenceNormalizer(normalizers []normalizers.Normalizer) *SequenceNormal
 	return &SequenceNormalizer{normalizers: normalizers}
 }
 
+// Normalizers returns the ordered sequence of Normalizers.
+func (sn *SequenceNormalizer) Normalizers() []normalizers.Normalizer {
+	return sn.normalizers
+}
+
 // Normalize transform the NormalizedString running the ordered sequence of
 // normalizers (against the same NormalizedString).
 //
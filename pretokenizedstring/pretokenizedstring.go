@@ -5,13 +5,17 @@
 package pretokenizedstring
 
 import (
-	"fmt"
+	"errors"
 	"github.com/nlpodyssey/gotokenizers/encodings"
 	"github.com/nlpodyssey/gotokenizers/models"
 	"github.com/nlpodyssey/gotokenizers/normalizedstring"
 	"github.com/nlpodyssey/gotokenizers/strutils"
 )
 
+// ErrNotTokenized is returned by PreTokenizedString.IntoEncoding when some
+// splits have not been tokenized.
+var ErrNotTokenized = errors.New("splits have not been tokenized, call `PreTokenizedString.Tokenize` first")
+
 // PreTokenizedString is in charge of splitting an underlying string,
 // making sure everything is fine while doing so, and providing ways to
 // normalize and tokenize these splits.
@@ -166,15 +170,17 @@ func (p *PreTokenizedString) Splits() []Split {
 // will be set to this value. This is generally used with pre-tokenized
 // input, that does not need the PreTokenizedString to generate word ids.
 //
-// This method will fail if some splits do not have associated Token.
+// This method fails with ErrNotTokenized if some splits do not have
+// associated Token.
 //
-// Offset indices are based on bytes (not runes).
+// Offset indices are based on bytes (not runes). If the alignments of the
+// NormalizedString are not tracked, all offsets are zero.
 func (p *PreTokenizedString) IntoEncoding(wordIndex int, typeID int) (*encodings.Encoding, error) {
 	if len(p.splits) == 0 {
 		return encodings.NewDefaultEncoding(), nil
 	}
 	if !p.allSplitsHaveTokens() {
-		return nil, fmt.Errorf("splits have not been tokenized, call `PreTokenizedString.Tokenize` first")
+		return nil, ErrNotTokenized
 	}
 
 	sequence := make([]encodings.EncodableToken, 0)
@@ -190,15 +196,17 @@ func (p *PreTokenizedString) IntoEncoding(wordIndex int, typeID int) (*encodings
 		for _, token := range *split.Tokens {
 			var offsets strutils.ByteOffsets
 
-			tokenOrigRange, ok := split.NormalizedString.CoerceRangeToOriginal(
-				normalizedstring.NewNormalizedRange(token.Offsets.Start, token.Offsets.End))
-			if ok {
-				offsets = strutils.ByteOffsets{
-					Start: nsOffsets.Start + tokenOrigRange.Start(),
-					End:   nsOffsets.Start + tokenOrigRange.End(),
+			if split.NormalizedString.AlignmentsTracked() {
+				tokenOrigRange, ok := split.NormalizedString.CoerceRangeToOriginal(
+					normalizedstring.NewNormalizedRange(token.Offsets.Start, token.Offsets.End))
+				if ok {
+					offsets = strutils.ByteOffsets{
+						Start: nsOffsets.Start + tokenOrigRange.Start(),
+						End:   nsOffsets.Start + tokenOrigRange.End(),
+					}
+				} else {
+					offsets = token.Offsets
 				}
-			} else {
-				offsets = token.Offsets
 			}
 
 			sequence = append(sequence, encodings.EncodableToken{
@@ -0,0 +1,36 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pretokenizedstring
+
+import (
+	"errors"
+	"github.com/nlpodyssey/gotokenizers/models"
+	"github.com/nlpodyssey/gotokenizers/normalizedstring"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"testing"
+)
+
+func TestPreTokenizedStringIntoEncodingNotTokenized(t *testing.T) {
+	t.Parallel()
+
+	pts := FromString("hello")
+	if _, err := pts.IntoEncoding(-1, 0); !errors.Is(err, ErrNotTokenized) {
+		t.Fatalf("expected ErrNotTokenized, actual %#v", err)
+	}
+
+	err := pts.Tokenize(func(ns *normalizedstring.NormalizedString) ([]models.Token, error) {
+		return []models.Token{{ID: 1, Value: ns.Get(), Offsets: strutils.ByteOffsets{Start: 0, End: len(ns.Get())}}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoding, err := pts.IntoEncoding(-1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encoding.IDs) != 1 || encoding.IDs[0] != 1 {
+		t.Errorf("unexpected IDs %v", encoding.IDs)
+	}
+}
@@ -6,11 +6,15 @@ package bytelevelpretokenizer
 
 import (
 	"github.com/dlclark/regexp2"
+	"github.com/nlpodyssey/gotokenizers/decoders"
+	"github.com/nlpodyssey/gotokenizers/encodings"
 	"github.com/nlpodyssey/gotokenizers/normalizedstring"
 	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
 	"github.com/nlpodyssey/gotokenizers/pretokenizers"
 	"github.com/nlpodyssey/gotokenizers/splitpattern"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // ByteLevelPreTokenizer allows the generation of pre-tokens suitable in the
@@ -25,21 +29,31 @@ import (
 // unless the first rune of the string is already a unicode whitespace.
 //
 // Offsets trimming can be enabled to exclude whitespaces in the post-processing
-// step.
+// step (see ProcessOffsets).
+//
+// ByteLevelPreTokenizer is also a decoders.Decoder, mapping the custom runes
+// back to the original bytes.
 type ByteLevelPreTokenizer struct {
 	splittingRegexp        *regexp2.Regexp
 	prefixSpaceEnabled     bool
 	offsetsTrimmingEnabled bool
 }
 
-var _ pretokenizers.PreTokenizer = &ByteLevelPreTokenizer{}
+var (
+	_ pretokenizers.PreTokenizer     = &ByteLevelPreTokenizer{}
+	_ pretokenizers.OffsetsProcessor = &ByteLevelPreTokenizer{}
+	_ decoders.Decoder               = &ByteLevelPreTokenizer{}
+)
 
 // DefaultSplittingRegexp is a simple default regular expression that
 // can be used for ByteLevelPreTokenizer.
 //
+// When it is used, the splitting is performed by the equivalent, and much
+// faster, splitpattern.NewGPT2Scanner.
+//
 // This MUST be treated as a read-only constant value .
 var DefaultSplittingRegexp = regexp2.MustCompile(
-	`'s|'t|'re|'ve|'m|'ll|'d| ?\p{L}+| ?\p{N}+| ?[^\s\p{L}\p{N}]+|\s+(?!\S)|\s+`,
+	splitpattern.GPT2Pattern,
 	regexp2.IgnoreCase|regexp2.Multiline)
 
 // New returns a new ByteLevelPreTokenizer.
@@ -64,9 +78,13 @@ func NewDefault() *ByteLevelPreTokenizer {
 // PreTokenize is in charge of transforming all the unicode characters into
 // their byte-level counterpart. It also splits the input according to the
 // configured regex.
+//
+// If a splitting regexp other than DefaultSplittingRegexp is given to New,
+// and its MatchTimeout is exceeded, the returned error is a
+// *splitpattern.MatchTimeoutError. DefaultSplittingRegexp is matched by
+// a linear-time scanner instead, so no timeout applies to it.
 func (b *ByteLevelPreTokenizer) PreTokenize(pts *pretokenizedstring.PreTokenizedString) error {
-	//let re_ref: &Regex = &RE;
-	splittingPattern := splitpattern.FromRegexp2(b.splittingRegexp)
+	splittingPattern := b.splittingPattern()
 
 	err := pts.Split(
 		func(_ int, ns *normalizedstring.NormalizedString) ([]pretokenizedstring.Split, error) {
@@ -109,12 +127,129 @@ func (b *ByteLevelPreTokenizer) PreTokenize(pts *pretokenizedstring.PreTokenized
 	})
 }
 
+// splittingPattern returns the SplitPattern for the splitting regexp.
+func (b *ByteLevelPreTokenizer) splittingPattern() splitpattern.SplitPattern {
+	if b.splittingRegexp == DefaultSplittingRegexp {
+		return splitpattern.NewGPT2Scanner()
+	}
+	return splitpattern.FromRegexp2(b.splittingRegexp)
+}
+
+// ProcessOffsets trims the leading and trailing whitespaces (usually the 'Ġ'
+// prefix) from the offsets of the tokens of the given Encoding, so that they
+// only cover the actual words. It does nothing if offsets trimming is not
+// enabled.
+//
+// The prefix space of the first token of the sequence is not trimmed when
+// prefix space insertion is enabled, since it was not part of the original
+// string, and its offsets already exclude it.
+func (b *ByteLevelPreTokenizer) ProcessOffsets(encoding *encodings.Encoding, sequenceStart bool) {
+	if !b.offsetsTrimmingEnabled {
+		return
+	}
+	for i, token := range encoding.Tokens {
+		offsets := &encoding.Offsets[i]
+		leading, leadingCount := leadingSpaces(token)
+		trailing := trailingSpaces(token)
+
+		if leading > 0 {
+			isFirst := (i == 0 && sequenceStart) || offsets.Start == 0
+			if isFirst && b.prefixSpaceEnabled && leadingCount == 1 {
+				leading = 0
+			}
+			offsets.Start = minInt(offsets.Start+leading, offsets.End)
+		}
+		if trailing > 0 && offsets.End >= trailing {
+			offsets.End = maxInt(offsets.End-trailing, offsets.Start)
+		}
+	}
+}
+
+// leadingSpaces returns the length in bytes, in the original string, of the
+// whitespaces at the beginning of the token, and their number.
+func leadingSpaces(token string) (size, count int) {
+	for _, r := range token {
+		n := spaceSize(r)
+		if n == 0 {
+			break
+		}
+		size += n
+		count++
+	}
+	return
+}
+
+// trailingSpaces returns the length in bytes, in the original string, of the
+// whitespaces at the end of the token.
+func trailingSpaces(token string) (size int) {
+	for len(token) > 0 {
+		r, rSize := utf8.DecodeLastRuneInString(token)
+		n := spaceSize(r)
+		if n == 0 {
+			break
+		}
+		size += n
+		token = token[:len(token)-rSize]
+	}
+	return
+}
+
+// spaceSize returns the length in bytes of the whitespace represented by the
+// given rune, either the byte-level 'Ġ', or a whitespace which is not mapped
+// to bytes (as in added tokens), or 0 if it is not a whitespace.
+func spaceSize(r rune) int {
+	switch {
+	case r == byteToRune[' ']:
+		return 1
+	case unicode.IsSpace(r):
+		return utf8.RuneLen(r)
+	default:
+		return 0
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// DecodeChain maps the runes of all the tokens back to the bytes they
+// represent, and returns the resulting string as a single decoded value.
+//
+// Invalid UTF-8 byte sequences are replaced with the Unicode replacement
+// character (U+FFFD). Runes which are not produced by the byte-level
+// mapping are kept as they are.
+func (b *ByteLevelPreTokenizer) DecodeChain(tokens []string) ([]string, error) {
+	bytes := make([]byte, 0)
+	for _, token := range tokens {
+		for _, r := range token {
+			if byteVal, ok := runeToByte[r]; ok {
+				bytes = append(bytes, byteVal)
+			} else {
+				bytes = append(bytes, string(r)...)
+			}
+		}
+	}
+	return []string{strings.ToValidUTF8(string(bytes), string(unicode.ReplacementChar))}, nil
+}
+
 func startsWithWhitespace(s string) bool {
 	return len(s) > 0 && unicode.In([]rune(s)[0], unicode.White_Space)
 }
 
 var byteToRune [0x100]rune
 
+var runeToByte = make(map[rune]byte, 0x100)
+
 func init() {
 	n := 0
 	for i := range byteToRune {
@@ -124,5 +259,6 @@ func init() {
 			byteToRune[i] = rune(0x100 + n)
 			n++
 		}
+		runeToByte[byteToRune[i]] = byte(i)
 	}
 }
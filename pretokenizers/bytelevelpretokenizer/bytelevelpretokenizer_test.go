@@ -5,11 +5,17 @@
 package bytelevelpretokenizer
 
 import (
+	"errors"
 	"fmt"
+	"github.com/dlclark/regexp2"
+	"github.com/nlpodyssey/gotokenizers/encodings"
 	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/splitpattern"
 	"github.com/nlpodyssey/gotokenizers/strutils"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestByteLevelPreTokenizer_PreTokenize(t *testing.T) {
@@ -147,6 +153,148 @@ func TestByteLevelPreTokenizer_PreTokenize(t *testing.T) {
 	})
 }
 
+func TestByteLevelPreTokenizer_PreTokenizeDefaultScanner(t *testing.T) {
+	t.Parallel()
+
+	// A distinct Regexp with the same pattern is not replaced by the scanner
+	regexpCopy := regexp2.MustCompile(DefaultSplittingRegexp.String(), regexp2.IgnoreCase|regexp2.Multiline)
+	fast := New(DefaultSplittingRegexp, true, true)
+	slow := New(regexpCopy, true, true)
+
+	inputs := []string{
+		"Hello my friend, how is your day going?",
+		"  I'M   here\n\n  with 123 numbers...  ",
+		"日本語 Ünïcödé ⭢ 😀  ",
+	}
+	for _, input := range inputs {
+		fastPts := pretokenizedstring.FromString(input)
+		if err := fast.PreTokenize(fastPts); err != nil {
+			t.Fatal(err)
+		}
+		slowPts := pretokenizedstring.FromString(input)
+		if err := slow.PreTokenize(slowPts); err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, fastPts.GetOriginalByteSplits(), slowPts.GetOriginalByteSplits())
+	}
+}
+
+func TestByteLevelPreTokenizer_PreTokenizeMatchTimeout(t *testing.T) {
+	t.Parallel()
+
+	r := regexp2.MustCompile(`(\w+)+$`, regexp2.None)
+	r.MatchTimeout = time.Millisecond
+	pt := New(r, false, true)
+
+	pts := pretokenizedstring.FromString(strings.Repeat("a", 40) + "!")
+	err := pt.PreTokenize(pts)
+
+	var timeoutErr *splitpattern.MatchTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *splitpattern.MatchTimeoutError, actual %#v", err)
+	}
+}
+
+func TestByteLevelPreTokenizer_ProcessOffsets(t *testing.T) {
+	t.Parallel()
+
+	newEncoding := func() *encodings.Encoding {
+		return &encodings.Encoding{
+			Tokens: []string{"ĠHello", "Ġworld", "!", "ĠĠ", "Ġfoo\t", "Ġ"},
+			Offsets: []strutils.ByteOffsets{
+				{Start: 0, End: 5},
+				{Start: 5, End: 11},
+				{Start: 11, End: 12},
+				{Start: 12, End: 14},
+				{Start: 14, End: 19},
+				{Start: 19, End: 20},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name               string
+		prefixSpaceEnabled bool
+		trimmingEnabled    bool
+		sequenceStart      bool
+		expected           []strutils.ByteOffsets
+	}{
+		{
+			"trimming disabled",
+			true, false, true,
+			newEncoding().Offsets,
+		},
+		{
+			"prefix space enabled",
+			true, true, true,
+			[]strutils.ByteOffsets{
+				{Start: 0, End: 5},
+				{Start: 6, End: 11},
+				{Start: 11, End: 12},
+				{Start: 14, End: 14},
+				{Start: 15, End: 18},
+				{Start: 20, End: 20},
+			},
+		},
+		{
+			"prefix space disabled",
+			false, true, true,
+			[]strutils.ByteOffsets{
+				{Start: 1, End: 5},
+				{Start: 6, End: 11},
+				{Start: 11, End: 12},
+				{Start: 14, End: 14},
+				{Start: 15, End: 18},
+				{Start: 20, End: 20},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		pt := New(DefaultSplittingRegexp, tc.prefixSpaceEnabled, tc.trimmingEnabled)
+		encoding := newEncoding()
+		pt.ProcessOffsets(encoding, tc.sequenceStart)
+		if !reflect.DeepEqual(encoding.Offsets, tc.expected) {
+			t.Errorf("%s: expected %v, actual %v", tc.name, tc.expected, encoding.Offsets)
+		}
+	}
+
+	// The first token of a chunk which is not at the start of the sequence
+	// is trimmed, as any other token
+	encoding := &encodings.Encoding{
+		Tokens:  []string{"Ġworld"},
+		Offsets: []strutils.ByteOffsets{{Start: 5, End: 11}},
+	}
+	NewDefault().ProcessOffsets(encoding, false)
+	assertEqual(t, encoding.Offsets, []strutils.ByteOffsets{{Start: 6, End: 11}})
+}
+
+func TestByteLevelPreTokenizer_DecodeChain(t *testing.T) {
+	t.Parallel()
+
+	pt := NewDefault()
+
+	decoded, err := pt.DecodeChain([]string{"Hello", "Ġmy", "Ġfriend", ",", "Ġhow", "Ġis", "Ġyour", "Ġday", "Ġgoing", "?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, decoded, []string{"Hello my friend, how is your day going?"})
+
+	// A multi-byte character split across two tokens
+	decoded, err = pt.DecodeChain([]string{"i", "âŃ", "¢", "j"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, decoded, []string{"i⭢j"})
+
+	// An incomplete UTF-8 sequence
+	decoded, err = pt.DecodeChain([]string{"i", "âŃ"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, decoded, []string{"i\uFFFD"})
+}
+
 func assertEqual(t *testing.T, actual, expected interface{}) {
 	t.Helper()
 	if !reflect.DeepEqual(actual, expected) {
@@ -0,0 +1,56 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package digitspretokenizer
+
+import (
+	"github.com/nlpodyssey/gotokenizers/normalizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers"
+	"github.com/nlpodyssey/gotokenizers/splitpattern"
+	"unicode"
+)
+
+// DigitsPreTokenizer allows the generation of pre-tokens splitting numeric
+// characters from the rest of the text.
+//
+// Sequences of contiguous digits are kept together, unless the splitting of
+// individual digits is enabled, in which case each digit becomes a separate
+// pre-token.
+type DigitsPreTokenizer struct {
+	individualDigits bool
+}
+
+var _ pretokenizers.PreTokenizer = &DigitsPreTokenizer{}
+
+// New returns a new DigitsPreTokenizer.
+func New(individualDigits bool) *DigitsPreTokenizer {
+	return &DigitsPreTokenizer{individualDigits: individualDigits}
+}
+
+// NewDefault returns a new DigitsPreTokenizer which keeps contiguous digits
+// together.
+func NewDefault() *DigitsPreTokenizer {
+	return New(false)
+}
+
+// PreTokenize splits the NormalizedString isolating numeric characters.
+func (d *DigitsPreTokenizer) PreTokenize(pts *pretokenizedstring.PreTokenizedString) error {
+	splittingPattern := splitpattern.FromFunc(unicode.IsNumber)
+
+	behavior := normalizedstring.SplitDelimiterContiguous
+	if d.individualDigits {
+		behavior = normalizedstring.SplitDelimiterIsolated
+	}
+
+	return pts.Split(
+		func(_ int, ns *normalizedstring.NormalizedString) ([]pretokenizedstring.Split, error) {
+			nss, err := ns.Split(splittingPattern, behavior)
+			if err != nil {
+				return nil, err
+			}
+			return pretokenizedstring.SplitsFromNormalizedStrings(nss), nil
+		},
+	)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package digitspretokenizer
+
+import (
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"reflect"
+	"testing"
+)
+
+func TestDigitsPreTokenizer_PreTokenize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Contiguous digits", func(t *testing.T) {
+		pt := NewDefault()
+		pts := pretokenizedstring.FromString("Hey 123 friend!")
+		err := pt.PreTokenize(pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, pts.GetOriginalByteSplits(), []pretokenizedstring.OriginalByteSplit{
+			{String: "Hey ", Offsets: strutils.ByteOffsets{Start: 0, End: 4}},
+			{String: "123", Offsets: strutils.ByteOffsets{Start: 4, End: 7}},
+			{String: " friend!", Offsets: strutils.ByteOffsets{Start: 7, End: 15}},
+		})
+	})
+
+	t.Run("Individual digits", func(t *testing.T) {
+		pt := New(true)
+		pts := pretokenizedstring.FromString("Hey 123 friend!")
+		err := pt.PreTokenize(pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, pts.GetOriginalByteSplits(), []pretokenizedstring.OriginalByteSplit{
+			{String: "Hey ", Offsets: strutils.ByteOffsets{Start: 0, End: 4}},
+			{String: "1", Offsets: strutils.ByteOffsets{Start: 4, End: 5}},
+			{String: "2", Offsets: strutils.ByteOffsets{Start: 5, End: 6}},
+			{String: "3", Offsets: strutils.ByteOffsets{Start: 6, End: 7}},
+			{String: " friend!", Offsets: strutils.ByteOffsets{Start: 7, End: 15}},
+		})
+	})
+
+	t.Run("Non-ASCII digits", func(t *testing.T) {
+		pt := NewDefault()
+		pts := pretokenizedstring.FromString("x٣٤y")
+		err := pt.PreTokenize(pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, pts.GetOriginalByteSplits(), []pretokenizedstring.OriginalByteSplit{
+			{String: "x", Offsets: strutils.ByteOffsets{Start: 0, End: 1}},
+			{String: "٣٤", Offsets: strutils.ByteOffsets{Start: 1, End: 5}},
+			{String: "y", Offsets: strutils.ByteOffsets{Start: 5, End: 6}},
+		})
+	})
+}
+
+func assertEqual(t *testing.T, actual, expected interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected\n  %#v\nactual\n  %#v", expected, actual)
+	}
+}
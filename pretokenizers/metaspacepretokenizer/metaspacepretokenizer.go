@@ -5,6 +5,7 @@
 package metaspacepretokenizer
 
 import (
+	"github.com/nlpodyssey/gotokenizers/decoders"
 	"github.com/nlpodyssey/gotokenizers/normalizedstring"
 	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
 	"github.com/nlpodyssey/gotokenizers/pretokenizers"
@@ -16,15 +17,39 @@ import (
 // replacing all the whitespace-like characters with the provided
 // meta-character (rune) and splitting the string by this character.
 //
-// A whitespace prefix (' ') can be optionally prepended to the input string,
-// unless the first rune of the string is already a unicode whitespace.
+// The meta-character can be optionally prepended to the input string,
+// unless the string already starts with it, according to the configured
+// PrependScheme. The final splitting step can be disabled, so that each
+// string is kept as a whole, with only its whitespaces replaced.
+//
+// MetaSpacePreTokenizer is also a decoders.Decoder, reverting the
+// meta-characters back to simple whitespaces.
 type MetaSpacePreTokenizer struct {
-	replacement        rune
-	strReplacement     string
-	prefixSpaceEnabled bool
+	replacement    rune
+	strReplacement string
+	prependScheme  PrependScheme
+	split          bool
 }
 
-var _ pretokenizers.PreTokenizer = &MetaSpacePreTokenizer{}
+var (
+	_ pretokenizers.PreTokenizer = &MetaSpacePreTokenizer{}
+	_ decoders.Decoder           = &MetaSpacePreTokenizer{}
+)
+
+// PrependScheme defines when the meta-character must be prepended to the
+// strings processed by a MetaSpacePreTokenizer.
+type PrependScheme uint8
+
+const (
+	// PrependAlways always prepends the meta-character to each string.
+	PrependAlways PrependScheme = iota
+	// PrependFirst only prepends the meta-character to the string
+	// which starts at the very beginning of the original input (i.e.
+	// the first section, when the input was previously split).
+	PrependFirst
+	// PrependNever never prepends the meta-character.
+	PrependNever
+)
 
 // DefaultReplacementCharacter is the default meta-character (rune) used to
 // initialize a NewDefault.
@@ -33,17 +58,36 @@ var _ pretokenizers.PreTokenizer = &MetaSpacePreTokenizer{}
 const DefaultReplacementCharacter = '▁'
 
 // New returns a new MetaSpacePreTokenizer.
+//
+// If prefixSpaceEnabled is true, the PrependScheme is set to PrependAlways,
+// otherwise to PrependNever. Splitting is always enabled.
 func New(replacement rune, prefixSpaceEnabled bool) *MetaSpacePreTokenizer {
+	prependScheme := PrependNever
+	if prefixSpaceEnabled {
+		prependScheme = PrependAlways
+	}
+	return NewWithPrependScheme(replacement, prependScheme, true)
+}
+
+// NewWithPrependScheme returns a new MetaSpacePreTokenizer, using the given
+// PrependScheme, and enabling or disabling the splitting of the string on
+// the meta-character.
+func NewWithPrependScheme(
+	replacement rune,
+	prependScheme PrependScheme,
+	split bool,
+) *MetaSpacePreTokenizer {
 	return &MetaSpacePreTokenizer{
-		replacement:        replacement,
-		strReplacement:     string(replacement),
-		prefixSpaceEnabled: prefixSpaceEnabled,
+		replacement:    replacement,
+		strReplacement: string(replacement),
+		prependScheme:  prependScheme,
+		split:          split,
 	}
 }
 
 // NewDefault returns a new MetaSpacePreTokenizer with
 // meta-character set to DefaultReplacementCharacter ('▁', i.e. lower one eighth
-// block U+2581), and prefix space enabled.
+// block U+2581), prefix space always enabled, and splitting enabled.
 func NewDefault() *MetaSpacePreTokenizer {
 	return New(DefaultReplacementCharacter, true)
 }
@@ -51,20 +95,25 @@ func NewDefault() *MetaSpacePreTokenizer {
 // PreTokenize virtually replaces all the whitespace-like characters with the
 // meta-character and splits the NormalizedString by this character.
 //
-// If whitespace prefix is enabled, a whitespace (' ') is prepended to
-// the NormalizedString, actually modifying its "normalized" value, only if
-// the first rune of the string is not already a unicode whitespace.
+// The meta-character is prepended to the NormalizedString, actually modifying
+// its "normalized" value, according to the PrependScheme, and only if the
+// string does not already start with the meta-character.
+//
+// If splitting is disabled, each NormalizedString is kept as a single split.
 func (m *MetaSpacePreTokenizer) PreTokenize(pts *pretokenizedstring.PreTokenizedString) error {
 	splittingPattern := splitpattern.FromRune(m.replacement)
 	return pts.Split(
 		func(_ int, ns *normalizedstring.NormalizedString) ([]pretokenizedstring.Split, error) {
-			if m.prefixSpaceEnabled && !strings.HasPrefix(ns.Get(), m.strReplacement) {
-				ns.Prepend(m.strReplacement)
-			}
 			err := ns.Replace(splitpattern.FromRune(' '), m.strReplacement)
 			if err != nil {
 				return nil, err
 			}
+			if m.shouldPrepend(ns) {
+				ns.Prepend(m.strReplacement)
+			}
+			if !m.split {
+				return []pretokenizedstring.Split{{NormalizedString: ns}}, nil
+			}
 			nss, err := ns.Split(splittingPattern, normalizedstring.SplitDelimiterMergedWithNext)
 			if err != nil {
 				return nil, err
@@ -73,3 +122,43 @@ func (m *MetaSpacePreTokenizer) PreTokenize(pts *pretokenizedstring.PreTokenized
 		},
 	)
 }
+
+// shouldPrepend reports whether the meta-character must be prepended to the
+// given NormalizedString, according to the PrependScheme.
+func (m *MetaSpacePreTokenizer) shouldPrepend(ns *normalizedstring.NormalizedString) bool {
+	if strings.HasPrefix(ns.Get(), m.strReplacement) {
+		return false
+	}
+	switch m.prependScheme {
+	case PrependAlways:
+		return true
+	case PrependFirst:
+		return ns.OriginalOffsets().Start == 0
+	default:
+		return false
+	}
+}
+
+// DecodeChain replaces the meta-characters with simple whitespaces.
+//
+// Unless the PrependScheme is PrependNever, the meta-characters of the first
+// token are removed, since they are expected to be the result of the
+// prepending.
+func (m *MetaSpacePreTokenizer) DecodeChain(tokens []string) ([]string, error) {
+	decoded := make([]string, len(tokens))
+	for i, token := range tokens {
+		var sb strings.Builder
+		for _, r := range token {
+			if r != m.replacement {
+				sb.WriteRune(r)
+				continue
+			}
+			if i == 0 && m.prependScheme != PrependNever {
+				continue
+			}
+			sb.WriteByte(' ')
+		}
+		decoded[i] = sb.String()
+	}
+	return decoded, nil
+}
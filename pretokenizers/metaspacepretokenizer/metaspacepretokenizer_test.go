@@ -6,7 +6,10 @@ package metaspacepretokenizer
 
 import (
 	"fmt"
+	"github.com/nlpodyssey/gotokenizers/decoders"
+	"github.com/nlpodyssey/gotokenizers/normalizedstring"
 	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/splitpattern"
 	"github.com/nlpodyssey/gotokenizers/strutils"
 	"reflect"
 	"testing"
@@ -63,6 +66,116 @@ func TestMetaSpacePreTokenizer_PreTokenize(t *testing.T) {
 	}
 }
 
+func TestMetaSpacePreTokenizer_PreTokenizeWithPrependScheme(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Leading whitespace", func(t *testing.T) {
+		pt := NewDefault()
+		pts := pretokenizedstring.FromString(" Hey friend!")
+		err := pt.PreTokenize(pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, pts.GetOriginalByteSplits(), []pretokenizedstring.OriginalByteSplit{
+			{String: "▁Hey", Offsets: strutils.ByteOffsets{Start: 0, End: 4}},
+			{String: "▁friend!", Offsets: strutils.ByteOffsets{Start: 4, End: 12}},
+		})
+	})
+
+	t.Run("PrependNever", func(t *testing.T) {
+		pt := NewWithPrependScheme(DefaultReplacementCharacter, PrependNever, true)
+		pts := pretokenizedstring.FromString("Hey friend!")
+		err := pt.PreTokenize(pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, pts.GetOriginalByteSplits(), []pretokenizedstring.OriginalByteSplit{
+			{String: "Hey", Offsets: strutils.ByteOffsets{Start: 0, End: 3}},
+			{String: "▁friend!", Offsets: strutils.ByteOffsets{Start: 3, End: 11}},
+		})
+	})
+
+	t.Run("PrependFirst", func(t *testing.T) {
+		pt := NewWithPrependScheme(DefaultReplacementCharacter, PrependFirst, true)
+		pts := pretokenizedstring.FromString("Hey friend!")
+		// Simulate a previous splitting step, such as special tokens extraction.
+		err := pts.Split(
+			func(_ int, ns *normalizedstring.NormalizedString) ([]pretokenizedstring.Split, error) {
+				nss, err := ns.Split(splitpattern.FromString("fr"), normalizedstring.SplitDelimiterIsolated)
+				if err != nil {
+					return nil, err
+				}
+				return pretokenizedstring.SplitsFromNormalizedStrings(nss), nil
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = pt.PreTokenize(pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, pts.GetOriginalByteSplits(), []pretokenizedstring.OriginalByteSplit{
+			{String: "▁Hey", Offsets: strutils.ByteOffsets{Start: 0, End: 3}},
+			{String: "▁", Offsets: strutils.ByteOffsets{Start: 3, End: 4}},
+			{String: "fr", Offsets: strutils.ByteOffsets{Start: 4, End: 6}},
+			{String: "iend!", Offsets: strutils.ByteOffsets{Start: 6, End: 11}},
+		})
+	})
+
+	t.Run("PrependFirst without splitting", func(t *testing.T) {
+		pt := NewWithPrependScheme(DefaultReplacementCharacter, PrependFirst, false)
+		pts := pretokenizedstring.FromString("Hey   friend!")
+		err := pt.PreTokenize(pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, pts.GetOriginalByteSplits(), []pretokenizedstring.OriginalByteSplit{
+			{String: "▁Hey▁▁▁friend!", Offsets: strutils.ByteOffsets{Start: 0, End: 13}},
+		})
+		assertEqual(t, pts.GetNormalizedByteSplits(), []pretokenizedstring.NormalizedByteSplit{
+			{String: "▁Hey▁▁▁friend!", Offsets: strutils.ByteOffsets{Start: 0, End: 22}},
+		})
+	})
+}
+
+func TestMetaSpacePreTokenizer_DecodeChain(t *testing.T) {
+	t.Parallel()
+
+	tokens := []string{"▁Hey", "▁friend", "!", "▁", "▁how"}
+
+	t.Run("PrependAlways", func(t *testing.T) {
+		decoded, err := NewDefault().DecodeChain(tokens)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, decoded, []string{"Hey", " friend", "!", " ", " how"})
+	})
+
+	t.Run("PrependFirst", func(t *testing.T) {
+		pt := NewWithPrependScheme(DefaultReplacementCharacter, PrependFirst, false)
+		decoded, err := decoders.Decode(pt, tokens)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, decoded, "Hey friend!  how")
+	})
+
+	t.Run("PrependNever", func(t *testing.T) {
+		pt := NewWithPrependScheme(DefaultReplacementCharacter, PrependNever, true)
+		decoded, err := decoders.Decode(pt, tokens)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, decoded, " Hey friend!  how")
+	})
+}
+
 func assertEqual(t *testing.T, actual, expected interface{}) {
 	t.Helper()
 	if !reflect.DeepEqual(actual, expected) {
@@ -4,7 +4,10 @@
 
 package pretokenizers
 
-import "github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+import (
+	"github.com/nlpodyssey/gotokenizers/encodings"
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+)
 
 // PreTokenizer is implemented by any value that has a PreTokenize method,
 // which takes care of performing a pre-segmentation step.
@@ -16,6 +19,16 @@ type PreTokenizer interface {
 	PreTokenize(pts *pretokenizedstring.PreTokenizedString) error
 }
 
+// OffsetsProcessor is optionally implemented by a PreTokenizer which needs
+// to adjust the offsets of the final Encoding, once the whole sequence has
+// been tokenized.
+type OffsetsProcessor interface {
+	// ProcessOffsets adjusts the offsets of the given Encoding. The
+	// sequenceStart flag reports whether the Encoding starts at the
+	// beginning of the sequence, rather than being a chunk of a longer text.
+	ProcessOffsets(encoding *encodings.Encoding, sequenceStart bool)
+}
+
 // PreToken represents a pre-tokenized substring, along with its offsets
 // position on the original string.
 type PreToken struct {
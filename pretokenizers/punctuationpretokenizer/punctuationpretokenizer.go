@@ -0,0 +1,59 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package punctuationpretokenizer
+
+import (
+	"github.com/nlpodyssey/gotokenizers/normalizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers"
+	"github.com/nlpodyssey/gotokenizers/splitpattern"
+	"unicode"
+)
+
+// PunctuationPreTokenizer allows the generation of pre-tokens splitting
+// the text on punctuation characters.
+//
+// A punctuation character is either an ASCII punctuation character (which
+// includes ASCII symbols, such as '$' or '+'), or any rune from the Unicode
+// punctuation category (P). The delimiters are handled according to the
+// configured SplitDelimiterBehavior.
+type PunctuationPreTokenizer struct {
+	behavior normalizedstring.SplitDelimiterBehavior
+}
+
+var _ pretokenizers.PreTokenizer = &PunctuationPreTokenizer{}
+
+// New returns a new PunctuationPreTokenizer.
+func New(behavior normalizedstring.SplitDelimiterBehavior) *PunctuationPreTokenizer {
+	return &PunctuationPreTokenizer{behavior: behavior}
+}
+
+// NewDefault returns a new PunctuationPreTokenizer, isolating each
+// punctuation character.
+func NewDefault() *PunctuationPreTokenizer {
+	return New(normalizedstring.SplitDelimiterIsolated)
+}
+
+// PreTokenize splits the NormalizedString on punctuation characters.
+func (p *PunctuationPreTokenizer) PreTokenize(pts *pretokenizedstring.PreTokenizedString) error {
+	splittingPattern := splitpattern.FromFunc(isPunctuation)
+	return pts.Split(
+		func(_ int, ns *normalizedstring.NormalizedString) ([]pretokenizedstring.Split, error) {
+			nss, err := ns.Split(splittingPattern, p.behavior)
+			if err != nil {
+				return nil, err
+			}
+			return pretokenizedstring.SplitsFromNormalizedStrings(nss), nil
+		},
+	)
+}
+
+// isPunctuation checks whether a character is a punctuation character.
+func isPunctuation(r rune) bool {
+	if r <= unicode.MaxASCII {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r)
+	}
+	return unicode.IsPunct(r)
+}
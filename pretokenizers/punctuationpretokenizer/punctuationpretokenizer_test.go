@@ -0,0 +1,75 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package punctuationpretokenizer
+
+import (
+	"github.com/nlpodyssey/gotokenizers/normalizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"reflect"
+	"testing"
+)
+
+func TestPunctuationPreTokenizer_PreTokenize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Isolated", func(t *testing.T) {
+		pt := NewDefault()
+		pts := pretokenizedstring.FromString("Hey friend!     How are you?!?")
+		err := pt.PreTokenize(pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, pts.GetOriginalByteSplits(), []pretokenizedstring.OriginalByteSplit{
+			{String: "Hey friend", Offsets: strutils.ByteOffsets{Start: 0, End: 10}},
+			{String: "!", Offsets: strutils.ByteOffsets{Start: 10, End: 11}},
+			{String: "     How are you", Offsets: strutils.ByteOffsets{Start: 11, End: 27}},
+			{String: "?", Offsets: strutils.ByteOffsets{Start: 27, End: 28}},
+			{String: "!", Offsets: strutils.ByteOffsets{Start: 28, End: 29}},
+			{String: "?", Offsets: strutils.ByteOffsets{Start: 29, End: 30}},
+		})
+	})
+
+	t.Run("Contiguous", func(t *testing.T) {
+		pt := New(normalizedstring.SplitDelimiterContiguous)
+		pts := pretokenizedstring.FromString("How are you?!? 1+1=2")
+		err := pt.PreTokenize(pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, pts.GetOriginalByteSplits(), []pretokenizedstring.OriginalByteSplit{
+			{String: "How are you", Offsets: strutils.ByteOffsets{Start: 0, End: 11}},
+			{String: "?!?", Offsets: strutils.ByteOffsets{Start: 11, End: 14}},
+			{String: " 1", Offsets: strutils.ByteOffsets{Start: 14, End: 16}},
+			{String: "+", Offsets: strutils.ByteOffsets{Start: 16, End: 17}},
+			{String: "1", Offsets: strutils.ByteOffsets{Start: 17, End: 18}},
+			{String: "=", Offsets: strutils.ByteOffsets{Start: 18, End: 19}},
+			{String: "2", Offsets: strutils.ByteOffsets{Start: 19, End: 20}},
+		})
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		pt := New(normalizedstring.SplitDelimiterRemoved)
+		pts := pretokenizedstring.FromString("«Ciao», disse.")
+		err := pt.PreTokenize(pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, pts.GetOriginalByteSplits(), []pretokenizedstring.OriginalByteSplit{
+			{String: "Ciao", Offsets: strutils.ByteOffsets{Start: 2, End: 6}},
+			{String: " disse", Offsets: strutils.ByteOffsets{Start: 9, End: 15}},
+		})
+	})
+}
+
+func assertEqual(t *testing.T, actual, expected interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected\n  %#v\nactual\n  %#v", expected, actual)
+	}
+}
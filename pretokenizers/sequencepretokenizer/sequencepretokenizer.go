@@ -0,0 +1,44 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sequencepretokenizer
+
+import (
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers"
+)
+
+// SequencePreTokenizer allows concatenating multiple other PreTokenizers as
+// a Sequence.
+type SequencePreTokenizer struct {
+	preTokenizers []pretokenizers.PreTokenizer
+}
+
+var _ pretokenizers.PreTokenizer = &SequencePreTokenizer{}
+
+// New returns a new SequencePreTokenizer, initializing it with the ordered
+// sequence of PreTokenizers.
+func New(preTokenizers []pretokenizers.PreTokenizer) *SequencePreTokenizer {
+	return &SequencePreTokenizer{preTokenizers: preTokenizers}
+}
+
+// PreTokenizers returns the ordered sequence of PreTokenizers.
+func (s *SequencePreTokenizer) PreTokenizers() []pretokenizers.PreTokenizer {
+	return s.preTokenizers
+}
+
+// PreTokenize runs the ordered sequence of PreTokenizers against the same
+// PreTokenizedString.
+//
+// If one PreTokenizer returns an error, the same error is returned and
+// the subsequent PreTokenizers (if any) are ignored.
+func (s *SequencePreTokenizer) PreTokenize(pts *pretokenizedstring.PreTokenizedString) error {
+	for _, preTokenizer := range s.preTokenizers {
+		err := preTokenizer.PreTokenize(pts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sequencepretokenizer
+
+import (
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/digitspretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/whitespacesplitpretokenizer"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"reflect"
+	"testing"
+)
+
+func TestSequencePreTokenizer_PreTokenize(t *testing.T) {
+	t.Parallel()
+
+	pt := New([]pretokenizers.PreTokenizer{
+		whitespacesplitpretokenizer.New(),
+		digitspretokenizer.New(true),
+	})
+	pts := pretokenizedstring.FromString("Hey friend!   How are you?!? 42")
+	err := pt.PreTokenize(pts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, pts.GetOriginalByteSplits(), []pretokenizedstring.OriginalByteSplit{
+		{String: "Hey", Offsets: strutils.ByteOffsets{Start: 0, End: 3}},
+		{String: "friend!", Offsets: strutils.ByteOffsets{Start: 4, End: 11}},
+		{String: "How", Offsets: strutils.ByteOffsets{Start: 14, End: 17}},
+		{String: "are", Offsets: strutils.ByteOffsets{Start: 18, End: 21}},
+		{String: "you?!?", Offsets: strutils.ByteOffsets{Start: 22, End: 28}},
+		{String: "4", Offsets: strutils.ByteOffsets{Start: 29, End: 30}},
+		{String: "2", Offsets: strutils.ByteOffsets{Start: 30, End: 31}},
+	})
+}
+
+func assertEqual(t *testing.T, actual, expected interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected\n  %#v\nactual\n  %#v", expected, actual)
+	}
+}
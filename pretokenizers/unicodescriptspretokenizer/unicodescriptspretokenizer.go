@@ -0,0 +1,151 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unicodescriptspretokenizer
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/gotokenizers/normalizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// UnicodeScriptsPreTokenizer allows the generation of pre-tokens splitting
+// the text whenever the Unicode script of the characters changes.
+//
+// Hiragana and Katakana characters (and the prolonged sound mark U+30FC) are
+// considered to belong to the same group of Han characters, so that Japanese
+// text is never split in the middle of a sentence. Simple whitespaces (' ')
+// do not belong to any script: they are attached to the previous characters
+// and never cause a split on their own.
+type UnicodeScriptsPreTokenizer struct{}
+
+var _ pretokenizers.PreTokenizer = &UnicodeScriptsPreTokenizer{}
+
+// New returns a new UnicodeScriptsPreTokenizer.
+func New() *UnicodeScriptsPreTokenizer {
+	return &UnicodeScriptsPreTokenizer{}
+}
+
+// PreTokenize splits the NormalizedString on Unicode script changes.
+func (u *UnicodeScriptsPreTokenizer) PreTokenize(pts *pretokenizedstring.PreTokenizedString) error {
+	return pts.Split(
+		func(_ int, ns *normalizedstring.NormalizedString) ([]pretokenizedstring.Split, error) {
+			s := ns.Get()
+			offsets := make([]int, 0)
+
+			lastScript := scriptNone
+			lookup := scriptLookup{}
+			for i, r := range s {
+				script := lookup.fixedScript(r)
+				if script != scriptAny && lastScript != scriptAny && lastScript != script {
+					offsets = append(offsets, i)
+				}
+				if script != scriptAny {
+					lastScript = script
+				}
+			}
+			offsets = append(offsets, len(s))
+
+			nss := make([]*normalizedstring.NormalizedString, 0, len(offsets))
+			for i := 1; i < len(offsets); i++ {
+				sliced, ok := ns.Slice(normalizedstring.NewNormalizedRange(offsets[i-1], offsets[i]))
+				if !ok {
+					return nil, fmt.Errorf("NormalizedString bad split")
+				}
+				nss = append(nss, sliced)
+			}
+			return pretokenizedstring.SplitsFromNormalizedStrings(nss), nil
+		},
+	)
+}
+
+const (
+	// scriptNone is used before any script has been seen.
+	scriptNone = ""
+	// scriptAny identifies characters which do not belong to any specific
+	// script.
+	scriptAny = "Any"
+)
+
+// commonScriptNames lists the most frequently used scripts, which are
+// looked up before all the others.
+var commonScriptNames = []string{
+	"Latin", "Common", "Han", "Hiragana", "Katakana", "Inherited", "Cyrillic",
+	"Arabic", "Greek", "Hangul", "Devanagari", "Hebrew", "Thai",
+}
+
+// scriptNames is the list of all Unicode script names known to the unicode
+// package: the commonScriptNames first, then the others, sorted.
+var scriptNames []string
+
+func init() {
+	scriptNames = make([]string, 0, len(unicode.Scripts))
+	scriptNames = append(scriptNames, commonScriptNames...)
+	others := make([]string, 0, len(unicode.Scripts))
+	for name := range unicode.Scripts {
+		if !isCommonScript(name) {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+	scriptNames = append(scriptNames, others...)
+}
+
+func isCommonScript(name string) bool {
+	for _, common := range commonScriptNames {
+		if name == common {
+			return true
+		}
+	}
+	return false
+}
+
+// scriptLookup finds the scripts of the runes of a string, remembering the
+// last script found, since consecutive runes usually share the same one.
+type scriptLookup struct {
+	lastName  string
+	lastTable *unicode.RangeTable
+}
+
+// fixedScript returns the script of the given rune, grouping Hiragana and
+// Katakana with Han, and treating the simple whitespace as scriptAny.
+func (sl *scriptLookup) fixedScript(r rune) string {
+	if r == 0x30FC {
+		return "Han"
+	}
+	if r == ' ' {
+		return scriptAny
+	}
+	switch script := sl.getScript(r); script {
+	case "Hiragana", "Katakana":
+		return "Han"
+	default:
+		return script
+	}
+}
+
+// getScript returns the name of the Unicode script the given rune belongs to,
+// or scriptAny if it cannot be found.
+func (sl *scriptLookup) getScript(r rune) string {
+	if r < utf8.RuneSelf {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' {
+			return "Latin"
+		}
+		return "Common"
+	}
+	if sl.lastTable != nil && unicode.Is(sl.lastTable, r) {
+		return sl.lastName
+	}
+	for _, name := range scriptNames {
+		if table := unicode.Scripts[name]; unicode.Is(table, r) {
+			sl.lastName, sl.lastTable = name, table
+			return name
+		}
+	}
+	return scriptAny
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package unicodescriptspretokenizer
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestUnicodeScriptsPreTokenizer_PreTokenize(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input                  string
+		expectedOriginalSplits []pretokenizedstring.OriginalByteSplit
+	}{
+		{
+			"どこで生れ。Yes",
+			[]pretokenizedstring.OriginalByteSplit{
+				{String: "どこで生れ", Offsets: strutils.ByteOffsets{Start: 0, End: 15}},
+				{String: "。", Offsets: strutils.ByteOffsets{Start: 15, End: 18}},
+				{String: "Yes", Offsets: strutils.ByteOffsets{Start: 18, End: 21}},
+			},
+		},
+		{
+			"关于",
+			[]pretokenizedstring.OriginalByteSplit{
+				{String: "关于", Offsets: strutils.ByteOffsets{Start: 0, End: 6}},
+			},
+		},
+		{
+			"Apples are りんご 林檎",
+			[]pretokenizedstring.OriginalByteSplit{
+				{String: "Apples are ", Offsets: strutils.ByteOffsets{Start: 0, End: 11}},
+				{String: "りんご 林檎", Offsets: strutils.ByteOffsets{Start: 11, End: 27}},
+			},
+		},
+		{
+			"Ελληνικά and English",
+			[]pretokenizedstring.OriginalByteSplit{
+				{String: "Ελληνικά ", Offsets: strutils.ByteOffsets{Start: 0, End: 17}},
+				{String: "and English", Offsets: strutils.ByteOffsets{Start: 17, End: 28}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%#v", tc.input), func(t *testing.T) {
+			pt := New()
+			pts := pretokenizedstring.FromString(tc.input)
+			err := pt.PreTokenize(pts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertEqual(t, pts.GetOriginalByteSplits(), tc.expectedOriginalSplits)
+		})
+	}
+}
+
+func TestFixedScript(t *testing.T) {
+	t.Parallel()
+
+	sl := &scriptLookup{}
+
+	assertEqual(t, sl.fixedScript('a'), "Latin")
+	assertEqual(t, sl.fixedScript('林'), "Han")
+	assertEqual(t, sl.fixedScript('り'), "Han")
+	assertEqual(t, sl.fixedScript('ア'), "Han")
+	assertEqual(t, sl.fixedScript('ー'), "Han")
+	assertEqual(t, sl.fixedScript(' '), scriptAny)
+	assertEqual(t, sl.fixedScript('。'), "Common")
+}
+
+func TestScriptLookupGetScript(t *testing.T) {
+	t.Parallel()
+
+	// The result must be the same as the one of a plain scan of all the
+	// scripts, whatever the previous rune was
+	linearScan := func(r rune) string {
+		for name, table := range unicode.Scripts {
+			if unicode.Is(table, r) {
+				return name
+			}
+		}
+		return scriptAny
+	}
+	sl := &scriptLookup{}
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		if r%7 != 0 && r > 0x20000 {
+			continue
+		}
+		if actual, expected := sl.getScript(r), linearScan(r); actual != expected {
+			t.Fatalf("%U: expected %q, actual %q", r, expected, actual)
+		}
+	}
+}
+
+func BenchmarkUnicodeScriptsPreTokenizer_PreTokenize(b *testing.B) {
+	s := strings.Repeat("Hello, 世界! Привет, мир! こんにちは ", 100)
+	pt := New()
+	for i := 0; i < b.N; i++ {
+		if err := pt.PreTokenize(pretokenizedstring.FromString(s)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected\n  %#v\nactual\n  %#v", expected, actual)
+	}
+}
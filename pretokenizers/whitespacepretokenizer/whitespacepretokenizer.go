@@ -10,6 +10,7 @@ import (
 	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
 	"github.com/nlpodyssey/gotokenizers/pretokenizers"
 	"github.com/nlpodyssey/gotokenizers/splitpattern"
+	"time"
 )
 
 // WhiteSpacePreTokenizer allows the generation of pre-tokens made by
@@ -20,8 +21,12 @@ type WhiteSpacePreTokenizer struct {
 	r *regexp2.Regexp
 }
 
+// DefaultWordPattern is the regular expression pattern of DefaultWordRegexp.
+const DefaultWordPattern = `\w+|[^\w\s]+`
+
+// DefaultWordRegexp is the default word regexp, without a MatchTimeout.
 // (readonly)
-var DefaultWordRegexp = regexp2.MustCompile(`\w+|[^\w\s]+`, regexp2.IgnoreCase|regexp2.Multiline)
+var DefaultWordRegexp = regexp2.MustCompile(DefaultWordPattern, regexp2.IgnoreCase|regexp2.Multiline)
 
 var _ pretokenizers.PreTokenizer = &WhiteSpacePreTokenizer{}
 
@@ -30,12 +35,31 @@ func New(r *regexp2.Regexp) *WhiteSpacePreTokenizer {
 	return &WhiteSpacePreTokenizer{r: r}
 }
 
+// NewDefault returns a new WhiteSpacePreTokenizer, using DefaultWordRegexp,
+// which has no MatchTimeout.
 func NewDefault() *WhiteSpacePreTokenizer {
 	return New(DefaultWordRegexp)
 }
 
+// NewDefaultWithMatchTimeout returns a new WhiteSpacePreTokenizer, using
+// a new regexp compiled from DefaultWordPattern, with the given MatchTimeout.
+func NewDefaultWithMatchTimeout(timeout time.Duration) *WhiteSpacePreTokenizer {
+	r := regexp2.MustCompile(DefaultWordPattern, regexp2.IgnoreCase|regexp2.Multiline)
+	r.MatchTimeout = timeout
+	return New(r)
+}
+
+// Regexp returns the regexp matching the words.
+func (w *WhiteSpacePreTokenizer) Regexp() *regexp2.Regexp {
+	return w.r
+}
+
 // PreTokenize splits the NormalizedString into word and non-word groups
 // separated by whitespace-like characters.
+//
+// If the regexp given to New has a MatchTimeout, and it is exceeded, the
+// returned error is a *splitpattern.MatchTimeoutError. The regexp used by
+// NewDefault has no timeout.
 func (w *WhiteSpacePreTokenizer) PreTokenize(pts *pretokenizedstring.PreTokenizedString) error {
 	splittingPattern := splitpattern.Invert(splitpattern.FromRegexp2(w.r))
 	return pts.Split(
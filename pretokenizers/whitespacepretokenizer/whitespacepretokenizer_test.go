@@ -6,10 +6,12 @@ package whitespacepretokenizer
 
 import (
 	"fmt"
+	"github.com/dlclark/regexp2"
 	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
 	"github.com/nlpodyssey/gotokenizers/strutils"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestWhiteSpacePreTokenizer_PreTokenize(t *testing.T) {
@@ -59,3 +61,21 @@ func assertEqual(t *testing.T, actual, expected interface{}) {
 		t.Errorf("expected\n  %#v\nactual\n  %#v", expected, actual)
 	}
 }
+
+func TestNewDefaultWithMatchTimeout(t *testing.T) {
+	t.Parallel()
+
+	w := NewDefaultWithMatchTimeout(time.Second)
+	if w.Regexp() == DefaultWordRegexp {
+		t.Fatal("expected a new regexp")
+	}
+	if w.Regexp().MatchTimeout != time.Second {
+		t.Errorf("expected MatchTimeout %v, actual %v", time.Second, w.Regexp().MatchTimeout)
+	}
+	if DefaultWordRegexp.MatchTimeout != regexp2.DefaultMatchTimeout {
+		t.Errorf("DefaultWordRegexp MatchTimeout changed to %v", DefaultWordRegexp.MatchTimeout)
+	}
+	if w.Regexp().String() != DefaultWordPattern {
+		t.Errorf("expected pattern %q, actual %q", DefaultWordPattern, w.Regexp().String())
+	}
+}
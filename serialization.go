@@ -0,0 +1,552 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gotokenizers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nlpodyssey/gotokenizers/decoders"
+	"github.com/nlpodyssey/gotokenizers/decoders/wordpiecedecoder"
+	"github.com/nlpodyssey/gotokenizers/models"
+	"github.com/nlpodyssey/gotokenizers/models/bpemodel"
+	"github.com/nlpodyssey/gotokenizers/models/wordpiecemodel"
+	"github.com/nlpodyssey/gotokenizers/normalizedstring"
+	"github.com/nlpodyssey/gotokenizers/normalizers"
+	"github.com/nlpodyssey/gotokenizers/normalizers/bertnormalizer"
+	"github.com/nlpodyssey/gotokenizers/normalizers/lowercasenormalizer"
+	"github.com/nlpodyssey/gotokenizers/normalizers/sequencenormalizer"
+	"github.com/nlpodyssey/gotokenizers/normalizers/stripnormalizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/bertpretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/bytelevelpretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/digitspretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/metaspacepretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/punctuationpretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/runedelimiterpretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/sequencepretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/unicodescriptspretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/whitespacepretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/whitespacesplitpretokenizer"
+	"github.com/nlpodyssey/gotokenizers/vocabulary"
+	"io/ioutil"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FromFile reads a Tokenizer from a JSON file, in the same format used by
+// Hugging Face's tokenizers library (usually named "tokenizer.json").
+//
+// See FromJSON for the supported components.
+func FromFile(filename string) (*Tokenizer, error) {
+	return FromFileWithOptions(filename, LoadOptions{})
+}
+
+// FromFileWithOptions is like FromFile, with the given LoadOptions.
+func FromFileWithOptions(filename string, opts LoadOptions) (*Tokenizer, error) {
+	rawData, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return FromJSONWithOptions(rawData, opts)
+}
+
+// LoadOptions are the options of FromJSONWithOptions and
+// FromFileWithOptions.
+type LoadOptions struct {
+	// WhitespaceMatchTimeout limits the time spent looking for each match
+	// by the Whitespace pre-tokenizer, the only one based on regexp2. When
+	// it is exceeded, encoding fails with a *splitpattern.MatchTimeoutError.
+	// Zero means no timeout. The ByteLevel pre-tokenizer is not affected,
+	// since it is matched in linear time.
+	WhitespaceMatchTimeout time.Duration
+}
+
+// FromJSON builds a Tokenizer from JSON data, in the same format used by
+// Hugging Face's tokenizers library.
+//
+// Only the normalizer, pre_tokenizer, model and decoder components are
+// used, and an error is returned if any of them is not supported.
+// Of the post-processor, only the trim_offsets setting of ByteLevel and
+// RobertaProcessing is used, to enable the offsets trimming of the
+// ByteLevel pre-tokenizer. Added tokens, truncation and padding settings
+// are currently ignored.
+func FromJSON(data []byte) (*Tokenizer, error) {
+	return FromJSONWithOptions(data, LoadOptions{})
+}
+
+// FromJSONWithOptions is like FromJSON, with the given LoadOptions.
+func FromJSONWithOptions(data []byte, opts LoadOptions) (*Tokenizer, error) {
+	var config struct {
+		Normalizer    json.RawMessage `json:"normalizer"`
+		PreTokenizer  json.RawMessage `json:"pre_tokenizer"`
+		Model         json.RawMessage `json:"model"`
+		Decoder       json.RawMessage `json:"decoder"`
+		PostProcessor json.RawMessage `json:"post_processor"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	normalizer, err := normalizerFromJSON(config.Normalizer)
+	if err != nil {
+		return nil, err
+	}
+	trimOffsets, err := postProcessorTrimsOffsets(config.PostProcessor)
+	if err != nil {
+		return nil, err
+	}
+	preTokenizer, err := preTokenizerFromJSON(config.PreTokenizer, trimOffsets, opts)
+	if err != nil {
+		return nil, err
+	}
+	model, err := modelFromJSON(config.Model)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := decoderFromJSON(config.Decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTokenizer(normalizer, preTokenizer, model, decoder), nil
+}
+
+// componentType reads the "type" field of a JSON component.
+// It returns an empty string for a null component.
+func componentType(data json.RawMessage) (string, error) {
+	if isJSONNull(data) {
+		return "", nil
+	}
+	var v struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return "", err
+	}
+	return v.Type, nil
+}
+
+func isJSONNull(data json.RawMessage) bool {
+	return len(data) == 0 || string(data) == "null"
+}
+
+func normalizerFromJSON(data json.RawMessage) (normalizers.Normalizer, error) {
+	typ, err := componentType(data)
+	if err != nil || isJSONNull(data) {
+		return nil, err
+	}
+
+	switch typ {
+	case "BertNormalizer":
+		var c struct {
+			CleanText          bool  `json:"clean_text"`
+			HandleChineseChars bool  `json:"handle_chinese_chars"`
+			StripAccents       *bool `json:"strip_accents"`
+			Lowercase          bool  `json:"lowercase"`
+		}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+		// When not specified, accents stripping follows lowercasing
+		stripAccents := c.Lowercase
+		if c.StripAccents != nil {
+			stripAccents = *c.StripAccents
+		}
+		return bertnormalizer.NewBertNormalizer(
+			c.CleanText, c.HandleChineseChars, stripAccents, c.Lowercase), nil
+	case "Lowercase":
+		return lowercasenormalizer.NewLowerCaseNormalizer(), nil
+	case "Strip":
+		var c struct {
+			StripLeft  bool `json:"strip_left"`
+			StripRight bool `json:"strip_right"`
+		}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+		return stripnormalizer.NewStripNormalizer(c.StripLeft, c.StripRight), nil
+	case "Sequence":
+		var c struct {
+			Normalizers []json.RawMessage `json:"normalizers"`
+		}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+		items := make([]normalizers.Normalizer, 0, len(c.Normalizers))
+		for _, itemData := range c.Normalizers {
+			item, err := normalizerFromJSON(itemData)
+			if err != nil {
+				return nil, err
+			}
+			if item != nil {
+				items = append(items, item)
+			}
+		}
+		return sequencenormalizer.NewSequenceNormalizer(items), nil
+	default:
+		return nil, fmt.Errorf("unsupported normalizer type %#v", typ)
+	}
+}
+
+// preTokenizerFromJSON builds a PreTokenizer. Unlike the pre-tokenizer
+// configuration, trimOffsets comes from the post-processor, which is in
+// charge of the offsets trimming in Hugging Face's tokenizers library.
+func preTokenizerFromJSON(
+	data json.RawMessage,
+	trimOffsets bool,
+	opts LoadOptions,
+) (pretokenizers.PreTokenizer, error) {
+	typ, err := componentType(data)
+	if err != nil || isJSONNull(data) {
+		return nil, err
+	}
+
+	switch typ {
+	case "BertPreTokenizer":
+		return bertpretokenizer.New(), nil
+	case "ByteLevel":
+		c := struct {
+			AddPrefixSpace bool `json:"add_prefix_space"`
+			UseRegex       bool `json:"use_regex"`
+		}{UseRegex: true}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+		if !c.UseRegex {
+			return nil, fmt.Errorf("unsupported ByteLevel pre-tokenizer option use_regex=false")
+		}
+		return bytelevelpretokenizer.New(
+			bytelevelpretokenizer.DefaultSplittingRegexp, c.AddPrefixSpace, trimOffsets), nil
+	case "Metaspace":
+		m, err := metaspaceFromJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	case "Whitespace":
+		if opts.WhitespaceMatchTimeout > 0 {
+			return whitespacepretokenizer.NewDefaultWithMatchTimeout(opts.WhitespaceMatchTimeout), nil
+		}
+		return whitespacepretokenizer.NewDefault(), nil
+	case "WhitespaceSplit":
+		return whitespacesplitpretokenizer.New(), nil
+	case "CharDelimiterSplit":
+		var c struct {
+			Delimiter string `json:"delimiter"`
+		}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+		delimiter, err := singleRune(c.Delimiter)
+		if err != nil {
+			return nil, err
+		}
+		return runedelimiterpretokenizer.New(delimiter), nil
+	case "Digits":
+		var c struct {
+			IndividualDigits bool `json:"individual_digits"`
+		}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+		return digitspretokenizer.New(c.IndividualDigits), nil
+	case "Punctuation":
+		c := struct {
+			Behavior string `json:"behavior"`
+		}{Behavior: "Isolated"}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+		behavior, err := splitDelimiterBehaviorFromString(c.Behavior)
+		if err != nil {
+			return nil, err
+		}
+		return punctuationpretokenizer.New(behavior), nil
+	case "UnicodeScripts":
+		return unicodescriptspretokenizer.New(), nil
+	case "Sequence":
+		var c struct {
+			PreTokenizers []json.RawMessage `json:"pretokenizers"`
+		}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+		items := make([]pretokenizers.PreTokenizer, 0, len(c.PreTokenizers))
+		for _, itemData := range c.PreTokenizers {
+			item, err := preTokenizerFromJSON(itemData, trimOffsets, opts)
+			if err != nil {
+				return nil, err
+			}
+			if item != nil {
+				items = append(items, item)
+			}
+		}
+		return sequencepretokenizer.New(items), nil
+	default:
+		return nil, fmt.Errorf("unsupported pre-tokenizer type %#v", typ)
+	}
+}
+
+// postProcessorTrimsOffsets reports whether the post-processor trims the
+// whitespaces from the offsets.
+func postProcessorTrimsOffsets(data json.RawMessage) (bool, error) {
+	typ, err := componentType(data)
+	if err != nil || isJSONNull(data) {
+		return false, err
+	}
+
+	switch typ {
+	case "ByteLevel", "RobertaProcessing":
+		c := struct {
+			TrimOffsets bool `json:"trim_offsets"`
+		}{TrimOffsets: true}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return false, err
+		}
+		return c.TrimOffsets, nil
+	case "Sequence":
+		var c struct {
+			Processors []json.RawMessage `json:"processors"`
+		}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return false, err
+		}
+		for _, itemData := range c.Processors {
+			trimOffsets, err := postProcessorTrimsOffsets(itemData)
+			if err != nil || trimOffsets {
+				return trimOffsets, err
+			}
+		}
+		return false, nil
+	default:
+		return false, nil
+	}
+}
+
+func metaspaceFromJSON(data json.RawMessage) (*metaspacepretokenizer.MetaSpacePreTokenizer, error) {
+	c := struct {
+		Replacement    string  `json:"replacement"`
+		AddPrefixSpace *bool   `json:"add_prefix_space"`
+		PrependScheme  *string `json:"prepend_scheme"`
+		Split          *bool   `json:"split"`
+	}{Replacement: string(metaspacepretokenizer.DefaultReplacementCharacter)}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	replacement, err := singleRune(c.Replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	prependScheme := metaspacepretokenizer.PrependAlways
+	switch {
+	case c.PrependScheme != nil:
+		switch *c.PrependScheme {
+		case "always":
+			prependScheme = metaspacepretokenizer.PrependAlways
+		case "first":
+			prependScheme = metaspacepretokenizer.PrependFirst
+		case "never":
+			prependScheme = metaspacepretokenizer.PrependNever
+		default:
+			return nil, fmt.Errorf("unsupported Metaspace prepend_scheme %#v", *c.PrependScheme)
+		}
+	case c.AddPrefixSpace != nil && !*c.AddPrefixSpace:
+		prependScheme = metaspacepretokenizer.PrependNever
+	}
+
+	split := true
+	if c.Split != nil {
+		split = *c.Split
+	}
+
+	return metaspacepretokenizer.NewWithPrependScheme(replacement, prependScheme, split), nil
+}
+
+func modelFromJSON(data json.RawMessage) (models.Model, error) {
+	if isJSONNull(data) {
+		return nil, fmt.Errorf("missing model")
+	}
+
+	var c struct {
+		Type                    string          `json:"type"`
+		Vocab                   map[string]int  `json:"vocab"`
+		Merges                  json.RawMessage `json:"merges"`
+		Dropout                 *float64        `json:"dropout"`
+		UnkToken                *string         `json:"unk_token"`
+		ContinuingSubwordPrefix *string         `json:"continuing_subword_prefix"`
+		EndOfWordSuffix         *string         `json:"end_of_word_suffix"`
+		FuseUnk                 bool            `json:"fuse_unk"`
+		MaxInputCharsPerWord    *int            `json:"max_input_chars_per_word"`
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	typ := c.Type
+	if typ == "" {
+		// Older files do not report the model type
+		switch {
+		case c.Merges != nil:
+			typ = "BPE"
+		case c.MaxInputCharsPerWord != nil:
+			typ = "WordPiece"
+		}
+	}
+
+	vocab := vocabulary.FromMap(c.Vocab)
+	if err := vocab.CheckDuplicateIDs(); err != nil {
+		return nil, err
+	}
+
+	switch typ {
+	case "BPE":
+		merges, err := mergesFromJSON(c.Merges)
+		if err != nil {
+			return nil, err
+		}
+		mergeMap, err := bpemodel.MergeMapFromPairs(
+			merges, vocab, len(stringOrEmpty(c.ContinuingSubwordPrefix)))
+		if err != nil {
+			return nil, err
+		}
+		dropout := 0.0
+		if c.Dropout != nil {
+			dropout = *c.Dropout
+		}
+		return bpemodel.New(
+			vocab,
+			mergeMap,
+			bpemodel.DefaultCacheCapacity,
+			dropout,
+			stringOrEmpty(c.UnkToken),
+			stringOrEmpty(c.ContinuingSubwordPrefix),
+			stringOrEmpty(c.EndOfWordSuffix),
+			c.FuseUnk,
+		), nil
+	case "WordPiece":
+		unknownToken := "[UNK]"
+		if c.UnkToken != nil {
+			unknownToken = *c.UnkToken
+		}
+		prefix := "##"
+		if c.ContinuingSubwordPrefix != nil {
+			prefix = *c.ContinuingSubwordPrefix
+		}
+		maxInputCharsPerWord := 100
+		if c.MaxInputCharsPerWord != nil {
+			maxInputCharsPerWord = *c.MaxInputCharsPerWord
+		}
+		return wordpiecemodel.New(vocab, unknownToken, prefix, maxInputCharsPerWord), nil
+	default:
+		return nil, fmt.Errorf("unsupported model type %#v", typ)
+	}
+}
+
+// mergesFromJSON reads the BPE merges, which can be represented either as
+// a list of space-separated pairs (e.g. "a b"), or as a list of two-items
+// lists (e.g. ["a", "b"]). Only the latter allows terms containing spaces.
+func mergesFromJSON(data json.RawMessage) ([][2]string, error) {
+	if isJSONNull(data) {
+		return nil, nil
+	}
+
+	var strs []string
+	if err := json.Unmarshal(data, &strs); err == nil {
+		merges := make([][2]string, len(strs))
+		for i, str := range strs {
+			terms := strings.Split(str, " ")
+			if len(terms) != 2 {
+				return nil, &bpemodel.MergesParseError{Line: i + 1, Reason: "malformed merges"}
+			}
+			merges[i] = [2]string{terms[0], terms[1]}
+		}
+		return merges, nil
+	}
+
+	var pairs [][]string
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return nil, err
+	}
+	merges := make([][2]string, len(pairs))
+	for i, pair := range pairs {
+		if len(pair) != 2 {
+			return nil, &bpemodel.MergesParseError{Line: i + 1, Reason: "malformed merges"}
+		}
+		merges[i] = [2]string{pair[0], pair[1]}
+	}
+	return merges, nil
+}
+
+func decoderFromJSON(data json.RawMessage) (decoders.Decoder, error) {
+	typ, err := componentType(data)
+	if err != nil || isJSONNull(data) {
+		return nil, err
+	}
+
+	switch typ {
+	case "ByteLevel":
+		c := struct {
+			AddPrefixSpace bool `json:"add_prefix_space"`
+			TrimOffsets    bool `json:"trim_offsets"`
+		}{AddPrefixSpace: true, TrimOffsets: true}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+		return bytelevelpretokenizer.New(
+			bytelevelpretokenizer.DefaultSplittingRegexp, c.AddPrefixSpace, c.TrimOffsets), nil
+	case "Metaspace":
+		m, err := metaspaceFromJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	case "WordPiece":
+		c := struct {
+			Prefix  string `json:"prefix"`
+			Cleanup bool   `json:"cleanup"`
+		}{Prefix: "##", Cleanup: true}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+		return wordpiecedecoder.New(c.Prefix, c.Cleanup), nil
+	default:
+		return nil, fmt.Errorf("unsupported decoder type %#v", typ)
+	}
+}
+
+func splitDelimiterBehaviorFromString(s string) (normalizedstring.SplitDelimiterBehavior, error) {
+	switch s {
+	case "Removed":
+		return normalizedstring.SplitDelimiterRemoved, nil
+	case "Isolated":
+		return normalizedstring.SplitDelimiterIsolated, nil
+	case "MergedWithPrevious":
+		return normalizedstring.SplitDelimiterMergedWithPrevious, nil
+	case "MergedWithNext":
+		return normalizedstring.SplitDelimiterMergedWithNext, nil
+	case "Contiguous":
+		return normalizedstring.SplitDelimiterContiguous, nil
+	default:
+		return 0, fmt.Errorf("unsupported split delimiter behavior %#v", s)
+	}
+}
+
+func singleRune(s string) (rune, error) {
+	if utf8.RuneCountInString(s) != 1 {
+		return 0, fmt.Errorf("expected a single character, actual %#v", s)
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	return r, nil
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
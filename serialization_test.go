@@ -0,0 +1,214 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gotokenizers
+
+import (
+	"bytes"
+	"github.com/dlclark/regexp2"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/bytelevelpretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/sequencepretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/whitespacepretokenizer"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestFromFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WordPiece", func(t *testing.T) {
+		tokenizer, err := FromFile("testdata/tokenizers/bert-tiny.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		encoding, err := tokenizer.Encode("Hello, UNAFFABLE world!")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, encoding.IDs, []int{4, 10, 6, 7, 8, 5, 9})
+		assertEqual(t, encoding.Tokens, []string{"hello", ",", "un", "##aff", "##able", "world", "!"})
+		assertEqual(t, encoding.Offsets, []strutils.ByteOffsets{
+			{Start: 0, End: 5},
+			{Start: 5, End: 6},
+			{Start: 7, End: 9},
+			{Start: 9, End: 12},
+			{Start: 12, End: 16},
+			{Start: 17, End: 22},
+			{Start: 22, End: 23},
+		})
+
+		decoded, err := tokenizer.Decode(encoding.IDs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, decoded, "hello, unaffable world!")
+	})
+
+	t.Run("BPE", func(t *testing.T) {
+		tokenizer, err := FromFile("testdata/tokenizers/gpt2-tiny.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		encoding, err := tokenizer.Encode("Hello world!")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, encoding.IDs, []int{12, 17, 0})
+		assertEqual(t, encoding.Tokens, []string{"Hello", "Ġworld", "!"})
+		assertEqual(t, encoding.Offsets, []strutils.ByteOffsets{
+			{Start: 0, End: 5},
+			{Start: 5, End: 11},
+			{Start: 11, End: 12},
+		})
+
+		decoded, err := tokenizer.Decode(encoding.IDs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, decoded, "Hello world!")
+	})
+}
+
+func TestFromJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Metaspace with prepend scheme", func(t *testing.T) {
+		tokenizer, err := FromJSON([]byte(`{
+			"pre_tokenizer": {"type": "Metaspace", "replacement": "▁", "prepend_scheme": "first", "split": false},
+			"decoder": {"type": "Metaspace", "replacement": "▁", "prepend_scheme": "first", "split": false},
+			"model": {"type": "BPE", "vocab": {"▁": 0, "a": 1, "b": 2, "▁a": 3}, "merges": [["▁", "a"]]}
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		encoding, err := tokenizer.Encode("a b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, encoding.Tokens, []string{"▁a", "▁", "b"})
+
+		decoded, err := tokenizer.Decode(encoding.IDs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, decoded, "a b")
+	})
+
+	t.Run("BPE merges of terms containing spaces", func(t *testing.T) {
+		tokenizer, err := FromJSON([]byte(`{
+			"model": {
+				"type": "BPE",
+				"vocab": {"a": 0, " ": 1, "b": 2, "a ": 3, "a b": 4},
+				"merges": [["a", " "], ["a ", "b"]]
+			}
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		encoding, err := tokenizer.Encode("a b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, encoding.Tokens, []string{"a b"})
+	})
+
+	t.Run("ByteLevel decoder settings", func(t *testing.T) {
+		for decoder, expected := range map[string]*bytelevelpretokenizer.ByteLevelPreTokenizer{
+			`{"type": "ByteLevel"}`: bytelevelpretokenizer.New(
+				bytelevelpretokenizer.DefaultSplittingRegexp, true, true),
+			`{"type": "ByteLevel", "add_prefix_space": false, "trim_offsets": false}`: bytelevelpretokenizer.New(
+				bytelevelpretokenizer.DefaultSplittingRegexp, false, false),
+		} {
+			tokenizer, err := FromJSON([]byte(`{
+				"decoder": ` + decoder + `,
+				"model": {"type": "WordPiece", "vocab": {"[UNK]": 0}}
+			}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, tokenizer.Decoder(), expected)
+		}
+	})
+
+	t.Run("ByteLevel offsets trimming", func(t *testing.T) {
+		data, err := ioutil.ReadFile("testdata/tokenizers/gpt2-tiny.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for postProcessor, expected := range map[string][]strutils.ByteOffsets{
+			`null`: {{Start: 0, End: 5}, {Start: 5, End: 11}, {Start: 11, End: 12}},
+			`{"type": "ByteLevel", "trim_offsets": true}`:                 {{Start: 0, End: 5}, {Start: 6, End: 11}, {Start: 11, End: 12}},
+			`{"type": "ByteLevel", "trim_offsets": false}`:                {{Start: 0, End: 5}, {Start: 5, End: 11}, {Start: 11, End: 12}},
+			`{"type": "RobertaProcessing", "trim_offsets": true}`:         {{Start: 0, End: 5}, {Start: 6, End: 11}, {Start: 11, End: 12}},
+			`{"type": "Sequence", "processors": [{"type": "ByteLevel"}]}`: {{Start: 0, End: 5}, {Start: 6, End: 11}, {Start: 11, End: 12}},
+			`{"type": "BertProcessing"}`:                                  {{Start: 0, End: 5}, {Start: 5, End: 11}, {Start: 11, End: 12}},
+		} {
+			tokenizer, err := FromJSON(bytes.Replace(data,
+				[]byte(`"post_processor": null`), []byte(`"post_processor": `+postProcessor), 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			encoding, err := tokenizer.Encode("Hello world!")
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, encoding.Offsets, expected)
+		}
+	})
+
+	t.Run("Unsupported components", func(t *testing.T) {
+		_, err := FromJSON([]byte(`{"normalizer": {"type": "NFKC"}, "model": {"type": "WordPiece", "vocab": {}}}`))
+		if err == nil || err.Error() != `unsupported normalizer type "NFKC"` {
+			t.Errorf("expected unsupported normalizer error, actual %#v", err)
+		}
+
+		_, err = FromJSON([]byte(`{"model": {"type": "Unigram", "vocab": []}}`))
+		if err == nil {
+			t.Error("expected unsupported model error, actual nil")
+		}
+	})
+
+	t.Run("Duplicate vocabulary IDs", func(t *testing.T) {
+		_, err := FromJSON([]byte(`{"model": {"type": "WordPiece", "vocab": {"[UNK]": 0, "foo": 1, "bar": 1}}}`))
+		if err == nil || err.Error() != `duplicate vocabulary ID 1, associated to terms ["bar" "foo"]` {
+			t.Errorf("expected duplicate ID error, actual %#v", err)
+		}
+	})
+}
+
+func TestFromJSONWithOptions(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{
+		"pre_tokenizer": {
+			"type": "Sequence",
+			"pretokenizers": [{"type": "ByteLevel"}, {"type": "Whitespace"}]
+		},
+		"model": {"type": "WordPiece", "vocab": {"[UNK]": 0}}
+	}`)
+
+	for _, timeout := range []time.Duration{0, time.Second} {
+		tokenizer, err := FromJSONWithOptions(data, LoadOptions{WhitespaceMatchTimeout: timeout})
+		if err != nil {
+			t.Fatal(err)
+		}
+		preTokenizers := tokenizer.PreTokenizer().(*sequencepretokenizer.SequencePreTokenizer).PreTokenizers()
+		r := preTokenizers[1].(*whitespacepretokenizer.WhiteSpacePreTokenizer).Regexp()
+		if timeout == 0 {
+			if r != whitespacepretokenizer.DefaultWordRegexp {
+				t.Error("expected the default word regexp")
+			}
+			continue
+		}
+		assertEqual(t, r.MatchTimeout, timeout)
+		assertEqual(t, whitespacepretokenizer.DefaultWordRegexp.MatchTimeout, regexp2.DefaultMatchTimeout)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package splitpattern
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+)
+
+// CheckedPattern wraps a SplitPattern, verifying that the captures it
+// returns satisfy the SplitPattern contract (see CheckCaptures).
+//
+// It is meant for testing and debugging custom SplitPattern
+// implementations, which would otherwise silently corrupt the offsets of
+// the split strings.
+type CheckedPattern struct {
+	sp SplitPattern
+}
+
+var _ SplitPattern = &CheckedPattern{}
+
+// Checked returns a new CheckedPattern wrapping the given SplitPattern.
+func Checked(sp SplitPattern) *CheckedPattern {
+	return &CheckedPattern{sp: sp}
+}
+
+func (cp *CheckedPattern) FindMatches(s string) ([]Capture, error) {
+	captures, err := cp.sp.FindMatches(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckCaptures(s, captures); err != nil {
+		return nil, fmt.Errorf("%T: %w", cp.sp, err)
+	}
+	return captures, nil
+}
+
+// CheckCaptures verifies that the captures found by a SplitPattern for the
+// string s satisfy the SplitPattern contract, returning a descriptive
+// error otherwise: the captures must be ordered and contiguous, cover the
+// whole string, and start and end on rune boundaries.
+//
+// Empty captures are allowed (for example, for regular expressions
+// matching the empty string), and an empty string can have no captures.
+func CheckCaptures(s string, captures []Capture) error {
+	if len(captures) == 0 {
+		if len(s) == 0 {
+			return nil
+		}
+		return fmt.Errorf("no captures for non-empty string %q", s)
+	}
+
+	prevEnd := 0
+	for i, c := range captures {
+		switch {
+		case c.Offsets.Start != prevEnd:
+			if i == 0 {
+				return fmt.Errorf("capture 0 %v does not start at the beginning of %q", c.Offsets, s)
+			}
+			return fmt.Errorf("capture %d %v is not contiguous to capture %d %v in %q",
+				i, c.Offsets, i-1, captures[i-1].Offsets, s)
+		case c.Offsets.End < c.Offsets.Start:
+			return fmt.Errorf("capture %d %v ends before its start in %q", i, c.Offsets, s)
+		case c.Offsets.End > len(s):
+			return fmt.Errorf("capture %d %v is out of bounds of %q (%d bytes)", i, c.Offsets, s, len(s))
+		case !strutils.IsRuneBoundary(s, c.Offsets.End):
+			return fmt.Errorf("capture %d %v does not end on a rune boundary in %q", i, c.Offsets, s)
+		}
+		prevEnd = c.Offsets.End
+	}
+
+	if prevEnd != len(s) {
+		return fmt.Errorf("captures end at byte %d, not covering the whole %q (%d bytes)", prevEnd, s, len(s))
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package splitpattern
+
+import (
+	"errors"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"testing"
+)
+
+func TestCheckCaptures(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		s        string
+		captures []Capture
+		err      string
+	}{
+		{"", nil, ""},
+		{"", []Capture{{strutils.ByteOffsets{Start: 0, End: 0}, false}}, ""},
+		{"ab", []Capture{
+			{strutils.ByteOffsets{Start: 0, End: 0}, true},
+			{strutils.ByteOffsets{Start: 0, End: 2}, false},
+		}, ""},
+		{"ab", nil, `no captures for non-empty string "ab"`},
+		{"ab", []Capture{
+			{strutils.ByteOffsets{Start: 1, End: 2}, false},
+		}, `capture 0 {1 2} does not start at the beginning of "ab"`},
+		{"abc", []Capture{
+			{strutils.ByteOffsets{Start: 0, End: 1}, false},
+			{strutils.ByteOffsets{Start: 2, End: 3}, true},
+		}, `capture 1 {2 3} is not contiguous to capture 0 {0 1} in "abc"`},
+		{"abc", []Capture{
+			{strutils.ByteOffsets{Start: 0, End: 2}, false},
+			{strutils.ByteOffsets{Start: 1, End: 3}, true},
+		}, `capture 1 {1 3} is not contiguous to capture 0 {0 2} in "abc"`},
+		{"abc", []Capture{
+			{strutils.ByteOffsets{Start: 0, End: 2}, false},
+			{strutils.ByteOffsets{Start: 2, End: 1}, true},
+		}, `capture 1 {2 1} ends before its start in "abc"`},
+		{"abc", []Capture{
+			{strutils.ByteOffsets{Start: 0, End: 4}, false},
+		}, `capture 0 {0 4} is out of bounds of "abc" (3 bytes)`},
+		{"é", []Capture{
+			{strutils.ByteOffsets{Start: 0, End: 1}, false},
+			{strutils.ByteOffsets{Start: 1, End: 2}, false},
+		}, `capture 0 {0 1} does not end on a rune boundary in "é"`},
+		{"éa", []Capture{
+			{strutils.ByteOffsets{Start: 0, End: 2}, false},
+		}, `captures end at byte 2, not covering the whole "éa" (3 bytes)`},
+	}
+
+	for _, tc := range testCases {
+		err := CheckCaptures(tc.s, tc.captures)
+		actual := ""
+		if err != nil {
+			actual = err.Error()
+		}
+		if actual != tc.err {
+			t.Errorf("%q %v: expected error %q, actual %q", tc.s, tc.captures, tc.err, actual)
+		}
+	}
+}
+
+type runeCountPattern struct{}
+
+func (runeCountPattern) FindMatches(s string) ([]Capture, error) {
+	return []Capture{{Offsets: strutils.ByteOffsets{Start: 0, End: len([]rune(s))}}}, nil
+}
+
+type failingPattern struct{}
+
+var errFailingPattern = errors.New("failing pattern")
+
+func (failingPattern) FindMatches(string) ([]Capture, error) {
+	return nil, errFailingPattern
+}
+
+func TestCheckedPattern(t *testing.T) {
+	t.Parallel()
+
+	runTest(t, Checked(FromRune('a')), "bab", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 1}, false},
+		{strutils.ByteOffsets{Start: 1, End: 2}, true},
+		{strutils.ByteOffsets{Start: 2, End: 3}, false},
+	})
+
+	_, err := Checked(runeCountPattern{}).FindMatches("日本")
+	expected := `splitpattern.runeCountPattern: capture 0 {0 2} does not end on a rune boundary in "日本"`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, actual %v", expected, err)
+	}
+
+	_, err = Checked(failingPattern{}).FindMatches("abc")
+	if !errors.Is(err, errFailingPattern) {
+		t.Errorf("expected %v, actual %v", errFailingPattern, err)
+	}
+}
@@ -27,10 +27,16 @@ func (sp *RegexpSplitPattern) FindMatches(s string) ([]Capture, error) {
 		}}, nil
 	}
 
+	return capturesFromMatches(s, sp.r.FindAllStringIndex(s, -1)), nil
+}
+
+// capturesFromMatches converts a list of non-overlapping ordered matches,
+// each one represented by its start and end byte positions, into a list of
+// Capture values covering the whole string.
+func capturesFromMatches(s string, matches [][]int) []Capture {
 	prev := 0
 	splits := make([]Capture, 0, len(s))
 
-	matches := sp.r.FindAllStringIndex(s, -1)
 	for _, match := range matches {
 		startByte := match[0]
 		endByte := match[1]
@@ -55,5 +61,5 @@ func (sp *RegexpSplitPattern) FindMatches(s string) ([]Capture, error) {
 		})
 	}
 
-	return splits, nil
+	return splits
 }
@@ -5,20 +5,56 @@
 package splitpattern
 
 import (
+	"fmt"
 	"github.com/dlclark/regexp2"
 	"github.com/nlpodyssey/gotokenizers/strutils"
+	"strings"
+	"time"
 )
 
+// Regexp2SplitPattern is a SplitPattern based on a regexp2.Regexp.
+//
+// Since regexp2 is a backtracking engine, some patterns can take an
+// exponential time to match. The time spent looking for each match can be
+// limited setting the MatchTimeout of the Regexp; when it is exceeded,
+// FindMatches returns a *MatchTimeoutError.
 type Regexp2SplitPattern struct {
 	r *regexp2.Regexp
 }
 
 var _ SplitPattern = &Regexp2SplitPattern{}
 
+// MatchTimeoutError is the error returned by Regexp2SplitPattern.FindMatches
+// when the MatchTimeout of the Regexp is exceeded.
+type MatchTimeoutError struct {
+	// The regular expression pattern.
+	Pattern string
+	// The MatchTimeout of the Regexp.
+	Timeout time.Duration
+	// The original error reported by regexp2.
+	Err error
+}
+
 func FromRegexp2(r *regexp2.Regexp) *Regexp2SplitPattern {
 	return &Regexp2SplitPattern{r: r}
 }
 
+// FromRegexp2WithTimeout compiles the given pattern with the given options,
+// and returns a new Regexp2SplitPattern, limiting the time spent looking
+// for each match to the given timeout.
+func FromRegexp2WithTimeout(
+	pattern string,
+	options regexp2.RegexOptions,
+	timeout time.Duration,
+) (*Regexp2SplitPattern, error) {
+	r, err := regexp2.Compile(pattern, options)
+	if err != nil {
+		return nil, err
+	}
+	r.MatchTimeout = timeout
+	return FromRegexp2(r), nil
+}
+
 func (sp *Regexp2SplitPattern) FindMatches(s string) ([]Capture, error) {
 	if len(s) == 0 {
 		return []Capture{{
@@ -33,7 +69,7 @@ func (sp *Regexp2SplitPattern) FindMatches(s string) ([]Capture, error) {
 
 	match, err := sp.r.FindStringMatch(s)
 	if err != nil {
-		return nil, err
+		return nil, sp.matchError(err)
 	}
 
 	for match != nil {
@@ -54,7 +90,7 @@ func (sp *Regexp2SplitPattern) FindMatches(s string) ([]Capture, error) {
 
 		match, err = sp.r.FindNextMatch(match)
 		if err != nil {
-			return nil, err
+			return nil, sp.matchError(err)
 		}
 	}
 
@@ -67,3 +103,35 @@ func (sp *Regexp2SplitPattern) FindMatches(s string) ([]Capture, error) {
 
 	return splits, nil
 }
+
+// matchError converts the timeout errors reported by regexp2 to
+// *MatchTimeoutError.
+func (sp *Regexp2SplitPattern) matchError(err error) error {
+	if sp.r.MatchTimeout == regexp2.DefaultMatchTimeout || !isRegexp2Timeout(err) {
+		return err
+	}
+	return &MatchTimeoutError{
+		Pattern: sp.r.String(),
+		Timeout: sp.r.MatchTimeout,
+		Err:     err,
+	}
+}
+
+// regexp2TimeoutPrefix is the beginning of the message of the timeout
+// errors reported by regexp2 v1.4.0 (see go.mod), which have no dedicated
+// type.
+const regexp2TimeoutPrefix = "match timeout"
+
+// isRegexp2Timeout reports whether the given error, returned by a regexp2
+// matching function, is caused by the MatchTimeout being exceeded.
+func isRegexp2Timeout(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), regexp2TimeoutPrefix)
+}
+
+func (e *MatchTimeoutError) Error() string {
+	return fmt.Sprintf("regexp2 pattern %q: match timeout after %v", e.Pattern, e.Timeout)
+}
+
+func (e *MatchTimeoutError) Unwrap() error {
+	return e.Err
+}
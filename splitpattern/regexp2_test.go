@@ -5,9 +5,13 @@
 package splitpattern
 
 import (
+	"errors"
 	"github.com/dlclark/regexp2"
 	"github.com/nlpodyssey/gotokenizers/strutils"
+	"io/ioutil"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestRegexp2SplitPatternFindMatches(t *testing.T) {
@@ -44,3 +48,58 @@ func TestRegexp2SplitPatternFindMatches(t *testing.T) {
 		{strutils.ByteOffsets{Start: 0, End: 3}, false},
 	})
 }
+
+func TestRegexp2SplitPatternMatchTimeout(t *testing.T) {
+	t.Parallel()
+
+	sp, err := FromRegexp2WithTimeout(`(a+)+$`, regexp2.None, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runTest(t, sp, "aaa", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 3}, true},
+	})
+
+	captures, err := sp.FindMatches(strings.Repeat("a", 40) + "!")
+	if captures != nil {
+		t.Errorf("expected nil captures, actual %v", captures)
+	}
+	var timeoutErr *MatchTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *MatchTimeoutError, actual %#v", err)
+	}
+	if timeoutErr.Pattern != `(a+)+$` || timeoutErr.Timeout != time.Millisecond {
+		t.Errorf("unexpected error fields %#v", timeoutErr)
+	}
+
+	_, err = FromRegexp2WithTimeout(`(`, regexp2.None, time.Millisecond)
+	if err == nil {
+		t.Error("expected compilation error")
+	}
+}
+
+func TestIsRegexp2Timeout(t *testing.T) {
+	t.Parallel()
+
+	// The error message checked by isRegexp2Timeout is not part of the
+	// regexp2 API: it must be verified again when the dependency changes.
+	goMod, err := ioutil.ReadFile("../go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(goMod), "github.com/dlclark/regexp2 v1.4.0\n") {
+		t.Error("regexp2 is not at v1.4.0: check the timeout error message and update this test")
+	}
+
+	r := regexp2.MustCompile(`(a+)+$`, regexp2.None)
+	r.MatchTimeout = time.Millisecond
+	_, err = r.FindStringMatch(strings.Repeat("a", 40) + "!")
+	if !isRegexp2Timeout(err) {
+		t.Errorf("expected a regexp2 timeout error, actual %#v", err)
+	}
+
+	if isRegexp2Timeout(nil) || isRegexp2Timeout(errors.New("other")) {
+		t.Error("unexpected timeout detection")
+	}
+}
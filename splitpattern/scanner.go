@@ -0,0 +1,367 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package splitpattern
+
+import (
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Regular expressions commonly used for splitting text before byte-level
+// BPE models, which are implemented without a regular expression engine by
+// the ScannerSplitPattern constructors.
+const (
+	// GPT2Pattern is the pattern of GPT-2 and RoBERTa, which is meant to be
+	// compiled by regexp2 with the IgnoreCase and Multiline options.
+	GPT2Pattern = `'s|'t|'re|'ve|'m|'ll|'d| ?\p{L}+| ?\p{N}+| ?[^\s\p{L}\p{N}]+|\s+(?!\S)|\s+`
+	// CL100KPattern is the pattern of the cl100k_base encoding (GPT-3.5 and
+	// GPT-4).
+	CL100KPattern = `(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+`
+	// O200KPattern is the pattern of the o200k_base encoding (GPT-4o).
+	O200KPattern = `[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+(?!\S)|\s+`
+)
+
+// ScannerSplitPattern is a SplitPattern finding the same matches as one of
+// GPT2Pattern, CL100KPattern, or O200KPattern, compiled with regexp2, using
+// a hand-written scanner, which is much faster than the regular expression
+// engine and runs in linear time.
+//
+// The input string is expected to be valid UTF-8. Invalid bytes are
+// handled one at a time, as if they were the Unicode replacement character.
+type ScannerSplitPattern struct {
+	// scan returns the end of the match starting at the given position,
+	// or -1 if there is no such match.
+	scan func(s string, pos int) int
+}
+
+var _ SplitPattern = &ScannerSplitPattern{}
+
+// NewGPT2Scanner returns a new ScannerSplitPattern, equivalent to
+// GPT2Pattern, compiled with the regexp2.IgnoreCase and regexp2.Multiline
+// options.
+func NewGPT2Scanner() *ScannerSplitPattern {
+	return &ScannerSplitPattern{scan: scanGPT2}
+}
+
+// NewCL100KScanner returns a new ScannerSplitPattern, equivalent to
+// CL100KPattern, compiled with no options.
+func NewCL100KScanner() *ScannerSplitPattern {
+	return &ScannerSplitPattern{scan: scanCL100K}
+}
+
+// NewO200KScanner returns a new ScannerSplitPattern, equivalent to
+// O200KPattern, compiled with no options.
+func NewO200KScanner() *ScannerSplitPattern {
+	return &ScannerSplitPattern{scan: scanO200K}
+}
+
+func (sp *ScannerSplitPattern) FindMatches(s string) ([]Capture, error) {
+	if len(s) == 0 {
+		return []Capture{{
+			Offsets: strutils.ByteOffsets{Start: 0, End: 0},
+			IsMatch: false,
+		}}, nil
+	}
+
+	splits := make([]Capture, 0, len(s)/4+1)
+	prev := 0
+	for pos := 0; pos < len(s); {
+		end := sp.scan(s, pos)
+		if end == -1 {
+			_, size := utf8.DecodeRuneInString(s[pos:])
+			pos += size
+			continue
+		}
+		if prev != pos {
+			splits = append(splits, Capture{
+				Offsets: strutils.ByteOffsets{Start: prev, End: pos},
+				IsMatch: false,
+			})
+		}
+		splits = append(splits, Capture{
+			Offsets: strutils.ByteOffsets{Start: pos, End: end},
+			IsMatch: true,
+		})
+		prev = end
+		pos = end
+	}
+
+	if prev != len(s) {
+		splits = append(splits, Capture{
+			Offsets: strutils.ByteOffsets{Start: prev, End: len(s)},
+			IsMatch: false,
+		})
+	}
+	return splits, nil
+}
+
+// runeClass is a predicate over runes, used as a regular expression
+// character class.
+type runeClass func(rune) bool
+
+// The character classes used by the patterns. As in regexp2, \s matches
+// unicode.IsSpace, and the Unicode categories use the tables of the
+// unicode package.
+var (
+	isSpace  runeClass = unicode.IsSpace
+	isLetter runeClass = unicode.IsLetter
+	isNumber runeClass = unicode.IsNumber
+	// The literal ' '
+	isBlank runeClass = func(r rune) bool {
+		return r == ' '
+	}
+	// [^\s\p{L}\p{N}]
+	isOther runeClass = func(r rune) bool {
+		return !unicode.IsSpace(r) && !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}
+	// [\r\n]
+	isNewline runeClass = func(r rune) bool {
+		return r == '\r' || r == '\n'
+	}
+	// [^\r\n\p{L}\p{N}]
+	isPrefix runeClass = func(r rune) bool {
+		return r != '\r' && r != '\n' && !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}
+	// [\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]
+	isUpperWord runeClass = func(r rune) bool {
+		return unicode.In(r, unicode.Lu, unicode.Lt, unicode.Lm, unicode.Lo, unicode.M)
+	}
+	// [\p{Ll}\p{Lm}\p{Lo}\p{M}]
+	isLowerWord runeClass = func(r rune) bool {
+		return unicode.In(r, unicode.Ll, unicode.Lm, unicode.Lo, unicode.M)
+	}
+	// [\r\n/]
+	isNewlineOrSlash runeClass = func(r rune) bool {
+		return r == '\r' || r == '\n' || r == '/'
+	}
+)
+
+// scanner provides the primitives for matching a pattern on a string,
+// optionally ignoring the case, as regexp2 does, lowering each rune with
+// unicode.ToLower before comparing it.
+type scanner struct {
+	s          string
+	ignoreCase bool
+}
+
+// at returns the rune starting at the given position, and its size, or
+// (utf8.RuneError, 0) at the end of the string.
+func (sc scanner) at(pos int) (rune, int) {
+	if pos >= len(sc.s) {
+		return utf8.RuneError, 0
+	}
+	r, size := rune(sc.s[pos]), 1
+	if r >= utf8.RuneSelf {
+		r, size = utf8.DecodeRuneInString(sc.s[pos:])
+	}
+	if sc.ignoreCase {
+		r = unicode.ToLower(r)
+	}
+	return r, size
+}
+
+// one matches a single rune of the given class, returning the position
+// after it, or -1.
+func (sc scanner) one(pos int, class runeClass) int {
+	r, size := sc.at(pos)
+	if size == 0 || !class(r) {
+		return -1
+	}
+	return pos + size
+}
+
+// literal matches the rune c, returning the position after it, or -1.
+func (sc scanner) literal(pos int, c rune) int {
+	r, size := sc.at(pos)
+	if size == 0 || r != c {
+		return -1
+	}
+	return pos + size
+}
+
+// star greedily matches at most max runes of the given class (no limit if
+// max is negative), returning the position after the last one.
+func (sc scanner) star(pos int, class runeClass, max int) int {
+	for n := 0; n != max; n++ {
+		next := sc.one(pos, class)
+		if next == -1 {
+			break
+		}
+		pos = next
+	}
+	return pos
+}
+
+// plus greedily matches one or more runes of the given class, returning
+// the position after the last one, or -1.
+func (sc scanner) plus(pos int, class runeClass) int {
+	if sc.one(pos, class) == -1 {
+		return -1
+	}
+	return sc.star(pos, class, -1)
+}
+
+// prefixedPlus matches `p?c+`, where p and c are the given classes,
+// backtracking to match `c+` alone if it fails after the prefix.
+func (sc scanner) prefixedPlus(pos int, prefix, class runeClass) int {
+	if next := sc.one(pos, prefix); next != -1 {
+		if end := sc.plus(next, class); end != -1 {
+			return end
+		}
+	}
+	return sc.plus(pos, class)
+}
+
+// contraction matches `'s|'t|'re|'ve|'m|'ll|'d`, ignoring the case.
+func (sc scanner) contraction(pos int) int {
+	pos = sc.literal(pos, '\'')
+	if pos == -1 {
+		return -1
+	}
+	ci := scanner{s: sc.s, ignoreCase: true}
+	r, size := ci.at(pos)
+	switch r {
+	case 's', 't', 'm', 'd':
+		return pos + size
+	case 'r', 'v':
+		return ci.literal(pos+size, 'e')
+	case 'l':
+		return ci.literal(pos+size, 'l')
+	}
+	return -1
+}
+
+// trailingSpaces matches `\s+(?!\S)|\s+`: a sequence of whitespaces
+// excluding the last one, if followed by a non-whitespace, unless it is
+// the only one.
+func (sc scanner) trailingSpaces(pos int) int {
+	end := sc.plus(pos, isSpace)
+	if end == -1 || end == len(sc.s) {
+		return end
+	}
+	_, size := utf8.DecodeLastRuneInString(sc.s[pos:end])
+	if end-size > pos {
+		return end - size
+	}
+	return end
+}
+
+// newlines matches `\s*[\r\n]+`: a sequence of whitespaces up to the last
+// newline it contains.
+func (sc scanner) newlines(pos int) int {
+	last := -1
+	for {
+		r, size := sc.at(pos)
+		if size == 0 || !isSpace(r) {
+			return last
+		}
+		pos += size
+		if isNewline(r) {
+			last = pos
+		}
+	}
+}
+
+// lowerWord matches `[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+`,
+// followed by an optional contraction.
+func (sc scanner) lowerWord(pos int) int {
+	// Backtrack from the longest upper part, to the first position where
+	// the lower part can start
+	start := sc.star(pos, isUpperWord, -1)
+	for sc.one(start, isLowerWord) == -1 {
+		if start == pos {
+			return -1
+		}
+		_, size := utf8.DecodeLastRuneInString(sc.s[pos:start])
+		start -= size
+	}
+	return sc.optionalContraction(sc.star(start, isLowerWord, -1))
+}
+
+// upperWord matches `[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*`,
+// followed by an optional contraction.
+func (sc scanner) upperWord(pos int) int {
+	end := sc.plus(pos, isUpperWord)
+	if end == -1 {
+		return -1
+	}
+	return sc.optionalContraction(sc.star(end, isLowerWord, -1))
+}
+
+func (sc scanner) optionalContraction(pos int) int {
+	if end := sc.contraction(pos); end != -1 {
+		return end
+	}
+	return pos
+}
+
+func scanGPT2(s string, pos int) int {
+	sc := scanner{s: s, ignoreCase: true}
+	if end := sc.contraction(pos); end != -1 {
+		return end
+	}
+	if end := sc.prefixedPlus(pos, isBlank, isLetter); end != -1 {
+		return end
+	}
+	if end := sc.prefixedPlus(pos, isBlank, isNumber); end != -1 {
+		return end
+	}
+	if end := sc.prefixedPlus(pos, isBlank, isOther); end != -1 {
+		return end
+	}
+	return sc.trailingSpaces(pos)
+}
+
+func scanCL100K(s string, pos int) int {
+	sc := scanner{s: s}
+	if end := sc.contraction(pos); end != -1 {
+		return end
+	}
+	if end := sc.prefixedPlus(pos, isPrefix, isLetter); end != -1 {
+		return end
+	}
+	if sc.one(pos, isNumber) != -1 {
+		return sc.star(pos, isNumber, 3)
+	}
+	if end := sc.prefixedPlus(pos, isBlank, isOther); end != -1 {
+		return sc.star(end, isNewline, -1)
+	}
+	if end := sc.newlines(pos); end != -1 {
+		return end
+	}
+	return sc.trailingSpaces(pos)
+}
+
+func scanO200K(s string, pos int) int {
+	sc := scanner{s: s}
+	prefixed := sc.one(pos, isPrefix)
+	if prefixed != -1 {
+		if end := sc.lowerWord(prefixed); end != -1 {
+			return end
+		}
+	}
+	if end := sc.lowerWord(pos); end != -1 {
+		return end
+	}
+	if prefixed != -1 {
+		if end := sc.upperWord(prefixed); end != -1 {
+			return end
+		}
+	}
+	if end := sc.upperWord(pos); end != -1 {
+		return end
+	}
+	if sc.one(pos, isNumber) != -1 {
+		return sc.star(pos, isNumber, 3)
+	}
+	if end := sc.prefixedPlus(pos, isBlank, isOther); end != -1 {
+		return sc.star(end, isNewlineOrSlash, -1)
+	}
+	if end := sc.newlines(pos); end != -1 {
+		return end
+	}
+	return sc.trailingSpaces(pos)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package splitpattern
+
+import (
+	"github.com/dlclark/regexp2"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+var scannerTestCases = []struct {
+	name    string
+	scanner *ScannerSplitPattern
+	regexp  *regexp2.Regexp
+}{
+	{"GPT2", NewGPT2Scanner(), regexp2.MustCompile(GPT2Pattern, regexp2.IgnoreCase|regexp2.Multiline)},
+	{"CL100K", NewCL100KScanner(), regexp2.MustCompile(CL100KPattern, regexp2.None)},
+	{"O200K", NewO200KScanner(), regexp2.MustCompile(O200KPattern, regexp2.None)},
+}
+
+func TestScannerSplitPatternFindMatches(t *testing.T) {
+	t.Parallel()
+
+	sp := NewGPT2Scanner()
+	runTest(t, sp, "Hello world!  I'M here\n", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 5}, true},
+		{strutils.ByteOffsets{Start: 5, End: 11}, true},
+		{strutils.ByteOffsets{Start: 11, End: 12}, true},
+		{strutils.ByteOffsets{Start: 12, End: 13}, true},
+		{strutils.ByteOffsets{Start: 13, End: 15}, true},
+		{strutils.ByteOffsets{Start: 15, End: 17}, true},
+		{strutils.ByteOffsets{Start: 17, End: 22}, true},
+		{strutils.ByteOffsets{Start: 22, End: 23}, true},
+	})
+	runTest(t, sp, "", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 0}, false},
+	})
+
+	runTest(t, NewCL100KScanner(), "12345 ok?\n\n", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 3}, true},
+		{strutils.ByteOffsets{Start: 3, End: 5}, true},
+		{strutils.ByteOffsets{Start: 5, End: 8}, true},
+		{strutils.ByteOffsets{Start: 8, End: 11}, true},
+	})
+
+	runTest(t, NewO200KScanner(), "Server's HTTP", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 8}, true},
+		{strutils.ByteOffsets{Start: 8, End: 13}, true},
+	})
+}
+
+// scannerTestRunes are runes of all the classes distinguished by the
+// patterns, including some whose lowercase belongs to a different class.
+var scannerTestRunes = []rune(
+	"aAzZsStTrReEvVmMlLdD'' \t\r\n\v\f/" +
+		"09٣Ⅻ½²" +
+		"éÉǅʰ日ßİḰः⃝" +
+		" \u0085 　 " +
+		".,!?-_(\"😀‍­",
+)
+
+func randomScannerTestString(rnd *rand.Rand, maxLen int) string {
+	var sb strings.Builder
+	for n := rnd.Intn(maxLen + 1); n > 0; n-- {
+		if rnd.Intn(8) == 0 {
+			// Any valid rune
+			r := rune(rnd.Intn(utf8.MaxRune + 1))
+			if utf8.ValidRune(r) {
+				sb.WriteRune(r)
+			}
+			continue
+		}
+		sb.WriteRune(scannerTestRunes[rnd.Intn(len(scannerTestRunes))])
+	}
+	return sb.String()
+}
+
+func TestScannerSplitPatternMatchesRegexp2(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(42))
+	inputs := []string{
+		"",
+		"Hello world",
+		"  leading and trailing  ",
+		"they're   'RE 'Ll 'S",
+		"a\r\n\r\n  b \n c/\n/",
+		"ǅemo HTTPServer éÉ Éé",
+		"x́́y ́",
+		"123456789 ٣٣٣٣",
+	}
+	for i := 0; i < 5000; i++ {
+		inputs = append(inputs, randomScannerTestString(rnd, 20))
+	}
+
+	for _, tc := range scannerTestCases {
+		expectedSP := FromRegexp2(tc.regexp)
+		for _, s := range inputs {
+			expected, err := expectedSP.FindMatches(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := tc.scanner.FindMatches(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("%s: %q: expected\n  %v\nactual\n  %v", tc.name, s, expected, actual)
+			}
+		}
+	}
+}
+
+func BenchmarkScannerSplitPattern(b *testing.B) {
+	s := strings.Repeat("The quick brown fox, who's 42 years old,\njumps over   the lazy dog. ", 100)
+
+	for _, tc := range scannerTestCases {
+		b.Run(tc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := tc.scanner.FindMatches(s); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(tc.name+"Regexp2", func(b *testing.B) {
+			sp := FromRegexp2(tc.regexp)
+			for i := 0; i < b.N; i++ {
+				if _, err := sp.FindMatches(s); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
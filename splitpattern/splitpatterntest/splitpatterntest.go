@@ -0,0 +1,67 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package splitpatterntest implements support for testing implementations
+// of splitpattern.SplitPattern.
+package splitpatterntest
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/gotokenizers/splitpattern"
+	"reflect"
+)
+
+// Inputs is the list of strings which TestSplitPattern always checks, in
+// addition to the given ones. It includes empty and whitespace-only strings,
+// multi-byte runes, and combining characters.
+var Inputs = []string{
+	"",
+	" ",
+	"a",
+	"Hello, world!",
+	"  leading and trailing spaces  ",
+	"tab\tnew\nline\r\n",
+	"Café Cafe\u0301",
+	"日本語のテキスト",
+	"emoji 😀👍🏽 and ZWJ 👩\u200d👩\u200d👧",
+	"ǅ İ ß ﬃ",
+	"\u200b\u00ad",
+}
+
+// TestSplitPattern tests a SplitPattern implementation, calling FindMatches
+// on Inputs and on the given additional inputs, and checking that the
+// resulting captures satisfy the SplitPattern contract (see
+// splitpattern.CheckCaptures), and that the same input always gives the
+// same captures.
+//
+// It returns the first error found, if any. It is meant to be called from
+// tests, as in:
+//
+//	if err := splitpatterntest.TestSplitPattern(myPattern); err != nil {
+//	    t.Fatal(err)
+//	}
+func TestSplitPattern(sp splitpattern.SplitPattern, inputs ...string) error {
+	allInputs := make([]string, 0, len(Inputs)+len(inputs))
+	allInputs = append(allInputs, Inputs...)
+	allInputs = append(allInputs, inputs...)
+
+	for _, s := range allInputs {
+		captures, err := sp.FindMatches(s)
+		if err != nil {
+			return fmt.Errorf("%T: FindMatches(%q): %w", sp, s, err)
+		}
+		if err := splitpattern.CheckCaptures(s, captures); err != nil {
+			return fmt.Errorf("%T: %w", sp, err)
+		}
+
+		again, err := sp.FindMatches(s)
+		if err != nil {
+			return fmt.Errorf("%T: FindMatches(%q) called again: %w", sp, s, err)
+		}
+		if !reflect.DeepEqual(again, captures) {
+			return fmt.Errorf("%T: FindMatches(%q) returned %v, then %v", sp, s, captures, again)
+		}
+	}
+	return nil
+}
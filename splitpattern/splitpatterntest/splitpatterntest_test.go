@@ -0,0 +1,102 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package splitpatterntest
+
+import (
+	"github.com/dlclark/regexp2"
+	"github.com/nlpodyssey/gotokenizers/splitpattern"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestSplitPatternBuiltin(t *testing.T) {
+	t.Parallel()
+
+	patterns := map[string]splitpattern.SplitPattern{
+		"FromFunc":       splitpattern.FromFunc(unicode.IsSpace),
+		"FromRune":       splitpattern.FromRune('é'),
+		"FromRegexp":     splitpattern.FromRegexp(regexp.MustCompile(`\p{L}+`)),
+		"FromRegexp2":    splitpattern.FromRegexp2(regexp2.MustCompile(`\s+(?!\S)|\s+`, regexp2.None)),
+		"FromString":     splitpattern.FromString("af"),
+		"FromStringCI":   splitpattern.FromStringWithOptions("CAFÉ", splitpattern.StringOptions{CaseInsensitive: true, WholeWord: true}),
+		"FromStrings":    splitpattern.FromStrings([]string{"a", "ab", "日本", "😀"}, true),
+		"Invert":         splitpattern.Invert(splitpattern.FromRune(' ')),
+		"EmptyString":    splitpattern.FromString(""),
+		"EmptyMatches":   splitpattern.FromRegexp(regexp.MustCompile(`x*`)),
+		"CheckedPattern": splitpattern.Checked(splitpattern.FromRune('a')),
+		"GPT2Scanner":    splitpattern.NewGPT2Scanner(),
+		"CL100KScanner":  splitpattern.NewCL100KScanner(),
+		"O200KScanner":   splitpattern.NewO200KScanner(),
+	}
+	for name, sp := range patterns {
+		if err := TestSplitPattern(sp, "abab", "xxaxx"); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+// brokenPattern returns the captures built by a function, ignoring the input.
+type brokenPattern func(s string) []splitpattern.Capture
+
+func (bp brokenPattern) FindMatches(s string) ([]splitpattern.Capture, error) {
+	return bp(s), nil
+}
+
+func TestSplitPatternBroken(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		pattern brokenPattern
+		err     string
+	}{
+		{
+			"split in the middle of a rune",
+			func(s string) []splitpattern.Capture {
+				for i, r := range s {
+					if utf8.RuneLen(r) > 1 {
+						return []splitpattern.Capture{
+							{Offsets: strutils.ByteOffsets{Start: 0, End: i + 1}},
+							{Offsets: strutils.ByteOffsets{Start: i + 1, End: len(s)}},
+						}
+					}
+				}
+				return []splitpattern.Capture{{Offsets: strutils.ByteOffsets{Start: 0, End: len(s)}}}
+			},
+			"does not end on a rune boundary",
+		},
+		{
+			"missing last capture",
+			func(s string) []splitpattern.Capture {
+				if len(s) < 2 {
+					return []splitpattern.Capture{{Offsets: strutils.ByteOffsets{Start: 0, End: len(s)}}}
+				}
+				return []splitpattern.Capture{{Offsets: strutils.ByteOffsets{Start: 0, End: 1}}}
+			},
+			"not covering the whole",
+		},
+		{
+			"not deterministic",
+			func() brokenPattern {
+				calls := 0
+				return func(s string) []splitpattern.Capture {
+					calls++
+					return []splitpattern.Capture{{Offsets: strutils.ByteOffsets{Start: 0, End: len(s)}, IsMatch: calls%2 == 0}}
+				}
+			}(),
+			"returned",
+		},
+	}
+	for _, tc := range testCases {
+		err := TestSplitPattern(tc.pattern)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expected error containing %q, actual %v", tc.name, tc.err, err)
+		}
+	}
+}
@@ -7,31 +7,94 @@ package splitpattern
 import (
 	"github.com/nlpodyssey/gotokenizers/strutils"
 	"regexp"
+	"unicode"
+	"unicode/utf8"
 )
 
 type StringSplitPattern struct {
-	s string
-	r *RegexpSplitPattern
+	s         string
+	r         *regexp.Regexp
+	wholeWord bool
 }
 
 var _ SplitPattern = &StringSplitPattern{}
 
+// StringOptions modifies the way a StringSplitPattern matches the string.
+type StringOptions struct {
+	// CaseInsensitive enables case-insensitive matching, using Unicode
+	// simple case folding (so that, for example, "k" also matches the
+	// Kelvin sign (U+212A), while "ß" does not match "ss").
+	CaseInsensitive bool
+	// WholeWord only allows the matches which are neither preceded nor
+	// followed by a letter, a combining mark, or a number.
+	WholeWord bool
+}
+
 func FromString(s string) *StringSplitPattern {
-	sp := &StringSplitPattern{s: s}
+	return FromStringWithOptions(s, StringOptions{})
+}
+
+// FromStringWithOptions returns a new StringSplitPattern, matching the
+// string s according to the given options.
+func FromStringWithOptions(s string, opts StringOptions) *StringSplitPattern {
+	sp := &StringSplitPattern{s: s, wholeWord: opts.WholeWord}
 	if len(s) > 0 {
-		sp.r = FromRegexp(regexp.MustCompile(regexp.QuoteMeta(s)))
+		expr := regexp.QuoteMeta(s)
+		if opts.CaseInsensitive {
+			expr = "(?i)" + expr
+		}
+		sp.r = regexp.MustCompile(expr)
 	}
 	return sp
 }
 
 func (sp *StringSplitPattern) FindMatches(s string) ([]Capture, error) {
-	if sp.r == nil {
+	if sp.r == nil || len(s) == 0 {
 		// If we try to find the matches with an empty string, just don't match anything
 		return []Capture{{
-			// FIXME: is len of runes (and not bytes) correct?
-			Offsets: strutils.ByteOffsets{Start: 0, End: len([]rune(s))},
+			Offsets: strutils.ByteOffsets{Start: 0, End: len(s)},
 			IsMatch: false,
 		}}, nil
 	}
-	return sp.r.FindMatches(s)
+	if !sp.wholeWord {
+		return capturesFromMatches(s, sp.r.FindAllStringIndex(s, -1)), nil
+	}
+
+	var matches [][]int
+	for pos := 0; pos < len(s); {
+		loc := sp.r.FindStringIndex(s[pos:])
+		if loc == nil {
+			break
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		if isWordBoundary(s, start, end) {
+			matches = append(matches, []int{start, end})
+			pos = end
+			continue
+		}
+		// Look for another match, which might overlap the discarded one
+		_, size := utf8.DecodeRuneInString(s[start:])
+		pos = start + size
+	}
+	return capturesFromMatches(s, matches), nil
+}
+
+// isWordBoundary reports whether the range [start, end) of s is neither
+// preceded nor followed by a word rune.
+func isWordBoundary(s string, start, end int) bool {
+	if start > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(s[:start]); isWordRune(r) {
+			return false
+		}
+	}
+	if end < len(s) {
+		if r, _ := utf8.DecodeRuneInString(s[end:]); isWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsNumber(r)
 }
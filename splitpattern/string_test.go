@@ -62,3 +62,64 @@ func TestStringSplitPatternFindMatches(t *testing.T) {
 		{strutils.ByteOffsets{Start: 0, End: 3}, false},
 	})
 }
+
+func TestStringSplitPatternCaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	sp := FromStringWithOptions("straße", StringOptions{CaseInsensitive: true})
+
+	runTest(t, sp, "STRAẞE Straße strasse", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 8}, true},
+		{strutils.ByteOffsets{Start: 8, End: 9}, false},
+		{strutils.ByteOffsets{Start: 9, End: 16}, true},
+		{strutils.ByteOffsets{Start: 16, End: 24}, false},
+	})
+
+	// The Kelvin sign (U+212A) folds to "k"
+	runTest(t, FromStringWithOptions("ok", StringOptions{CaseInsensitive: true}), "O\u212a", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 4}, true},
+	})
+}
+
+func TestStringSplitPatternWholeWord(t *testing.T) {
+	t.Parallel()
+
+	sp := FromStringWithOptions("ab", StringOptions{WholeWord: true})
+
+	runTest(t, sp, "ab abc cab ab.", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 2}, true},
+		{strutils.ByteOffsets{Start: 2, End: 11}, false},
+		{strutils.ByteOffsets{Start: 11, End: 13}, true},
+		{strutils.ByteOffsets{Start: 13, End: 14}, false},
+	})
+
+	// Non-ASCII letters, numbers and combining marks are word runes
+	runTest(t, sp, "éab ab2 ab\u0301 _ab_", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 15}, false},
+		{strutils.ByteOffsets{Start: 15, End: 17}, true},
+		{strutils.ByteOffsets{Start: 17, End: 18}, false},
+	})
+
+	// A discarded match does not prevent an overlapping one
+	runTest(t, FromStringWithOptions("aa", StringOptions{WholeWord: true}), "baa aaa aa", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 8}, false},
+		{strutils.ByteOffsets{Start: 8, End: 10}, true},
+	})
+	runTest(t, FromStringWithOptions("a-", StringOptions{WholeWord: true}), "aa- a-", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 4}, false},
+		{strutils.ByteOffsets{Start: 4, End: 6}, true},
+	})
+}
+
+func TestStringSplitPatternCaseInsensitiveWholeWord(t *testing.T) {
+	t.Parallel()
+
+	sp := FromStringWithOptions("[mask]", StringOptions{CaseInsensitive: true, WholeWord: true})
+
+	runTest(t, sp, "a [MASK] b[Mask] [mask]", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 2}, false},
+		{strutils.ByteOffsets{Start: 2, End: 8}, true},
+		{strutils.ByteOffsets{Start: 8, End: 17}, false},
+		{strutils.ByteOffsets{Start: 17, End: 23}, true},
+	})
+}
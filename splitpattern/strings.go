@@ -0,0 +1,219 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package splitpattern
+
+import (
+	"github.com/nlpodyssey/gotokenizers/strutils"
+)
+
+// StringsSplitPattern is a SplitPattern matching any of multiple strings
+// at once, using an Aho-Corasick automaton, so that the time needed to find
+// the matches does not depend on the number of patterns.
+//
+// The matches never overlap. When multiple patterns match at the same
+// leftmost position, the first one in the list of patterns is chosen
+// (leftmost-first semantics, as in regular expression alternations), or
+// the longest one (leftmost-longest semantics).
+type StringsSplitPattern struct {
+	patterns        []string
+	leftmostLongest bool
+	// The nodes of the automaton; nodes[0] is the root.
+	nodes []ahoCorasickNode
+}
+
+var _ SplitPattern = &StringsSplitPattern{}
+
+// PatternCapture is a Capture produced by a StringsSplitPattern, which also
+// reports which pattern matched.
+type PatternCapture struct {
+	Capture
+	// The index of the matching pattern, or -1 if IsMatch is false.
+	Pattern int
+}
+
+type ahoCorasickNode struct {
+	next map[byte]int
+	// The node of the longest proper suffix of this node's string which is
+	// also in the trie.
+	fail int
+	// The index of the pattern ending at this node, or -1.
+	output int
+	// The nearest node in the chain of fail links with an output, or -1.
+	outputLink int
+	// The length of this node's string.
+	depth int
+}
+
+// FromStrings returns a new StringsSplitPattern, matching any of the given
+// patterns. If leftmostLongest is true, the longest pattern is chosen among
+// the ones matching at the same position, otherwise the first one.
+//
+// Empty patterns never match.
+func FromStrings(patterns []string, leftmostLongest bool) *StringsSplitPattern {
+	sp := &StringsSplitPattern{
+		patterns:        patterns,
+		leftmostLongest: leftmostLongest,
+		nodes:           []ahoCorasickNode{newAhoCorasickNode(0)},
+	}
+	for i, pattern := range patterns {
+		sp.insert(pattern, i)
+	}
+	sp.buildLinks()
+	return sp
+}
+
+func newAhoCorasickNode(depth int) ahoCorasickNode {
+	return ahoCorasickNode{
+		next:       make(map[byte]int),
+		output:     -1,
+		outputLink: -1,
+		depth:      depth,
+	}
+}
+
+// Patterns returns the patterns of the StringsSplitPattern.
+func (sp *StringsSplitPattern) Patterns() []string {
+	return sp.patterns
+}
+
+func (sp *StringsSplitPattern) insert(pattern string, index int) {
+	if len(pattern) == 0 {
+		return
+	}
+	node := 0
+	for i := 0; i < len(pattern); i++ {
+		next, ok := sp.nodes[node].next[pattern[i]]
+		if !ok {
+			next = len(sp.nodes)
+			sp.nodes = append(sp.nodes, newAhoCorasickNode(i+1))
+			sp.nodes[node].next[pattern[i]] = next
+		}
+		node = next
+	}
+	// In case of duplicates, the first pattern is kept
+	if sp.nodes[node].output == -1 {
+		sp.nodes[node].output = index
+	}
+}
+
+// buildLinks computes the fail and output links, visiting the trie
+// breadth-first.
+func (sp *StringsSplitPattern) buildLinks() {
+	queue := make([]int, 0, len(sp.nodes))
+	for _, child := range sp.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for b, child := range sp.nodes[node].next {
+			fail := sp.step(sp.nodes[node].fail, b)
+			if fail == child {
+				fail = 0
+			}
+			sp.nodes[child].fail = fail
+			if sp.nodes[fail].output != -1 {
+				sp.nodes[child].outputLink = fail
+			} else {
+				sp.nodes[child].outputLink = sp.nodes[fail].outputLink
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// step returns the node reached from the given one, reading the byte b.
+func (sp *StringsSplitPattern) step(node int, b byte) int {
+	for {
+		if next, ok := sp.nodes[node].next[b]; ok {
+			return next
+		}
+		if node == 0 {
+			return 0
+		}
+		node = sp.nodes[node].fail
+	}
+}
+
+func (sp *StringsSplitPattern) FindMatches(s string) ([]Capture, error) {
+	patternCaptures := sp.FindPatternMatches(s)
+	captures := make([]Capture, len(patternCaptures))
+	for i, pc := range patternCaptures {
+		captures[i] = pc.Capture
+	}
+	return captures, nil
+}
+
+// FindPatternMatches is like FindMatches, also reporting which pattern
+// produced each match.
+func (sp *StringsSplitPattern) FindPatternMatches(s string) []PatternCapture {
+	if len(s) == 0 {
+		return []PatternCapture{{
+			Capture: Capture{Offsets: strutils.ByteOffsets{Start: 0, End: 0}, IsMatch: false},
+			Pattern: -1,
+		}}
+	}
+
+	var matches [][]int
+	var matchPatterns []int
+
+	for pos := 0; pos < len(s); {
+		bestStart, bestEnd, bestPattern := -1, -1, -1
+		node := 0
+		for i := pos; i < len(s); i++ {
+			node = sp.step(node, s[i])
+
+			out := node
+			if sp.nodes[out].output == -1 {
+				out = sp.nodes[out].outputLink
+			}
+			for ; out != -1; out = sp.nodes[out].outputLink {
+				pattern := sp.nodes[out].output
+				end := i + 1
+				start := end - sp.nodes[out].depth
+				if sp.isBetterMatch(start, end, pattern, bestStart, bestEnd, bestPattern) {
+					bestStart, bestEnd, bestPattern = start, end, pattern
+				}
+			}
+
+			// Any further match would start after the current best one
+			if bestStart != -1 && i+1-sp.nodes[node].depth > bestStart {
+				break
+			}
+		}
+		if bestStart == -1 {
+			break
+		}
+		matches = append(matches, []int{bestStart, bestEnd})
+		matchPatterns = append(matchPatterns, bestPattern)
+		pos = bestEnd
+	}
+
+	captures := capturesFromMatches(s, matches)
+	patternCaptures := make([]PatternCapture, len(captures))
+	m := 0
+	for i, c := range captures {
+		pattern := -1
+		if c.IsMatch {
+			pattern = matchPatterns[m]
+			m++
+		}
+		patternCaptures[i] = PatternCapture{Capture: c, Pattern: pattern}
+	}
+	return patternCaptures
+}
+
+func (sp *StringsSplitPattern) isBetterMatch(start, end, pattern, bestStart, bestEnd, bestPattern int) bool {
+	switch {
+	case bestStart == -1 || start < bestStart:
+		return true
+	case start > bestStart:
+		return false
+	case sp.leftmostLongest && end != bestEnd:
+		return end > bestEnd
+	default:
+		return pattern < bestPattern
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package splitpattern
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStringsSplitPatternFindMatches(t *testing.T) {
+	t.Parallel()
+
+	runTest(t, FromStrings([]string{"Sam", "Samwise"}, false), "Samwise", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 3}, true},
+		{strutils.ByteOffsets{Start: 3, End: 7}, false},
+	})
+	runTest(t, FromStrings([]string{"Sam", "Samwise"}, true), "Samwise", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 7}, true},
+	})
+	runTest(t, FromStrings([]string{"abcd", "bc"}, false), "abce", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 1}, false},
+		{strutils.ByteOffsets{Start: 1, End: 3}, true},
+		{strutils.ByteOffsets{Start: 3, End: 4}, false},
+	})
+	runTest(t, FromStrings([]string{"[CLS]", "[SEP]", "日本"}, false), "[CLS] 日本語[SEP]", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 5}, true},
+		{strutils.ByteOffsets{Start: 5, End: 6}, false},
+		{strutils.ByteOffsets{Start: 6, End: 12}, true},
+		{strutils.ByteOffsets{Start: 12, End: 15}, false},
+		{strutils.ByteOffsets{Start: 15, End: 20}, true},
+	})
+	runTest(t, FromStrings([]string{"", "x"}, false), "aaa", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 3}, false},
+	})
+	runTest(t, FromStrings(nil, false), "", []Capture{
+		{strutils.ByteOffsets{Start: 0, End: 0}, false},
+	})
+}
+
+func TestStringsSplitPatternFindPatternMatches(t *testing.T) {
+	t.Parallel()
+
+	sp := FromStrings([]string{"he", "she", "hers", "his", "he"}, true)
+	actual := sp.FindPatternMatches("ushers his")
+	expected := []PatternCapture{
+		{Capture{strutils.ByteOffsets{Start: 0, End: 1}, false}, -1},
+		{Capture{strutils.ByteOffsets{Start: 1, End: 4}, true}, 1},
+		{Capture{strutils.ByteOffsets{Start: 4, End: 7}, false}, -1},
+		{Capture{strutils.ByteOffsets{Start: 7, End: 10}, true}, 3},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected\n  %#v\nactual\n  %#v", expected, actual)
+	}
+
+	// Duplicate patterns are reported with the first index
+	actual = sp.FindPatternMatches("he")
+	expected = []PatternCapture{
+		{Capture{strutils.ByteOffsets{Start: 0, End: 2}, true}, 0},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected\n  %#v\nactual\n  %#v", expected, actual)
+	}
+}
+
+func TestStringsSplitPatternRandom(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(42))
+	randomString := func(maxLen int) string {
+		var sb strings.Builder
+		for n := rnd.Intn(maxLen + 1); n > 0; n-- {
+			sb.WriteByte("abc"[rnd.Intn(3)])
+		}
+		return sb.String()
+	}
+
+	for i := 0; i < 2000; i++ {
+		patterns := make([]string, 1+rnd.Intn(6))
+		for j := range patterns {
+			patterns[j] = randomString(4)
+		}
+		s := randomString(20)
+		for _, leftmostLongest := range []bool{false, true} {
+			actual := FromStrings(patterns, leftmostLongest).FindPatternMatches(s)
+			expected := naiveFindPatternMatches(patterns, leftmostLongest, s)
+			if !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("patterns %q, leftmostLongest %t, input %q: expected\n  %v\nactual\n  %v",
+					patterns, leftmostLongest, s, expected, actual)
+			}
+		}
+	}
+}
+
+// naiveFindPatternMatches is a straightforward implementation of
+// StringsSplitPattern.FindPatternMatches.
+func naiveFindPatternMatches(patterns []string, leftmostLongest bool, s string) []PatternCapture {
+	var captures []PatternCapture
+	prev := 0
+	for pos := 0; pos < len(s); {
+		best := -1
+		for i, p := range patterns {
+			if p == "" || !strings.HasPrefix(s[pos:], p) {
+				continue
+			}
+			if best == -1 || (leftmostLongest && len(p) > len(patterns[best])) {
+				best = i
+			}
+		}
+		if best == -1 {
+			pos++
+			continue
+		}
+		if prev != pos {
+			captures = append(captures, PatternCapture{Capture{strutils.ByteOffsets{Start: prev, End: pos}, false}, -1})
+		}
+		end := pos + len(patterns[best])
+		captures = append(captures, PatternCapture{Capture{strutils.ByteOffsets{Start: pos, End: end}, true}, best})
+		pos, prev = end, end
+	}
+	if prev != len(s) || len(s) == 0 {
+		captures = append(captures, PatternCapture{Capture{strutils.ByteOffsets{Start: prev, End: len(s)}, false}, -1})
+	}
+	return captures
+}
+
+func BenchmarkStringsSplitPattern(b *testing.B) {
+	patterns := make([]string, 500)
+	for i := range patterns {
+		patterns[i] = fmt.Sprintf("<extra_id_%d>", i)
+	}
+	s := strings.Repeat("Hello <extra_id_42> world <extra_id_499>! ", 1000)
+
+	b.Run("FromStrings", func(b *testing.B) {
+		sp := FromStrings(patterns, false)
+		b.SetBytes(int64(len(s)))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := sp.FindMatches(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("FromString", func(b *testing.B) {
+		sps := make([]*StringSplitPattern, len(patterns))
+		for i, p := range patterns {
+			sps[i] = FromString(p)
+		}
+		b.SetBytes(int64(len(s)))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, sp := range sps {
+				if _, err := sp.FindMatches(s); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gotokenizers
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/gotokenizers/encodings"
+	"github.com/nlpodyssey/gotokenizers/normalizedstring"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultStreamChunkSize is the default approximate size in bytes of the
+// chunks read by a StreamEncoder.
+const DefaultStreamChunkSize = 64 * 1024
+
+// StreamEncoder encodes a text read from an io.Reader, chunk by chunk,
+// so that the whole text is never kept in memory.
+//
+// Its interface is similar to bufio.Scanner: successive calls to Scan step
+// through the Encodings of the chunks, which are returned by Encoding.
+//
+// The text is cut right before a whitespace which precedes a non-whitespace
+// character, so that words are never split. This way, the result is the
+// same as encoding the whole text at once, as long as the normalizer and
+// the pre-tokenizer do not depend on the context beyond the words (as with
+// BERT, GPT-2, or Metaspace pipelines). If no such boundary is found within
+// four times the chunk size, the text is cut at an arbitrary character.
+//
+// The offsets of the Encodings are byte positions relative to the whole
+// stream, and the word indices keep increasing from one chunk to the next.
+type StreamEncoder struct {
+	tokenizer *Tokenizer
+	reader    io.Reader
+	chunkSize int
+	// Data read, and not yet encoded.
+	buf []byte
+	eof bool
+	// Position of buf[0] in the stream.
+	offset int
+	// Number of words (pre-tokenized splits) of the previous chunks.
+	words    int
+	encoding *encodings.Encoding
+	err      error
+}
+
+// NewStreamEncoder returns a new StreamEncoder, reading from r.
+//
+// If chunkSize is not positive, DefaultStreamChunkSize is used.
+func (t *Tokenizer) NewStreamEncoder(r io.Reader, chunkSize int) *StreamEncoder {
+	if chunkSize <= 0 {
+		chunkSize = DefaultStreamChunkSize
+	}
+	return &StreamEncoder{
+		tokenizer: t,
+		reader:    r,
+		chunkSize: chunkSize,
+		buf:       make([]byte, 0, chunkSize),
+	}
+}
+
+// Scan advances the StreamEncoder to the next non-empty Encoding, which will
+// then be available through the Encoding method. It returns false when the
+// end of the input is reached, or an error occurs; after Scan returns false,
+// the Err method will return any error that occurred.
+func (s *StreamEncoder) Scan() bool {
+	s.encoding = nil
+	if s.err != nil {
+		return false
+	}
+
+	for {
+		chunk, ok := s.nextChunk()
+		if !ok {
+			return false
+		}
+
+		pts, err := s.tokenizer.tokenize(normalizedstring.FromString(chunk), nil)
+		if err != nil {
+			s.err = err
+			return false
+		}
+		encoding, err := pts.IntoEncoding(-1, 0)
+		if err != nil {
+			s.err = err
+			return false
+		}
+		chunkOffset := s.offset - len(chunk)
+		chunkWords := s.words
+		s.words += len(pts.Splits())
+		if encoding.Len() == 0 {
+			continue
+		}
+
+		for i, o := range encoding.Offsets {
+			encoding.Offsets[i] = strutils.ByteOffsets{
+				Start: o.Start + chunkOffset,
+				End:   o.End + chunkOffset,
+			}
+		}
+		for i, w := range encoding.Words {
+			encoding.Words[i] = w + chunkWords
+		}
+		s.tokenizer.processOffsets(encoding, chunkOffset == 0)
+
+		s.encoding = encoding
+		return true
+	}
+}
+
+// Encoding returns the most recent Encoding generated by a call to Scan.
+func (s *StreamEncoder) Encoding() *encodings.Encoding {
+	return s.encoding
+}
+
+// Err returns the first error that was encountered by the StreamEncoder.
+func (s *StreamEncoder) Err() error {
+	return s.err
+}
+
+// nextChunk returns the next chunk of text to be encoded, and false if there
+// are no more chunks, or an error occurred.
+func (s *StreamEncoder) nextChunk() (string, bool) {
+	target := s.chunkSize
+	for {
+		if err := s.fill(target); err != nil {
+			s.err = err
+			return "", false
+		}
+		if len(s.buf) == 0 {
+			return "", false
+		}
+
+		cut := len(s.buf)
+		if !s.eof {
+			cut = lastWordBoundary(s.buf)
+			if cut == 0 && len(s.buf) >= 4*s.chunkSize {
+				cut = lastRuneBoundary(s.buf)
+			}
+			if cut == 0 {
+				target += s.chunkSize
+				continue
+			}
+		}
+
+		chunk := string(s.buf[:cut])
+		s.buf = s.buf[:copy(s.buf, s.buf[cut:])]
+		s.offset += cut
+		return chunk, true
+	}
+}
+
+// fill reads from the underlying reader until the buffer contains at least
+// size bytes, or the end of the input is reached.
+func (s *StreamEncoder) fill(size int) error {
+	for !s.eof && len(s.buf) < size {
+		if len(s.buf) == cap(s.buf) {
+			buf := make([]byte, len(s.buf), 2*cap(s.buf)+1)
+			copy(buf, s.buf)
+			s.buf = buf
+		}
+		n, err := s.reader.Read(s.buf[len(s.buf):cap(s.buf)])
+		s.buf = s.buf[:len(s.buf)+n]
+		if err == io.EOF {
+			s.eof = true
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lastWordBoundary returns the position of the last whitespace rune which
+// is followed by a non-whitespace rune, or 0 if there is none.
+func lastWordBoundary(b []byte) int {
+	end := len(b)
+	// The last rune might be incomplete, or followed by more whitespaces
+	_, size := utf8.DecodeLastRune(b[:end])
+	end -= size
+
+	nextIsSpace := true
+	for end > 0 {
+		r, size := utf8.DecodeLastRune(b[:end])
+		start := end - size
+		isSpace := unicode.IsSpace(r)
+		if isSpace && !nextIsSpace && start > 0 {
+			return start
+		}
+		nextIsSpace = isSpace
+		end = start
+	}
+	return 0
+}
+
+// lastRuneBoundary returns the position of the start of the last rune,
+// which might be incomplete.
+func lastRuneBoundary(b []byte) int {
+	for i := len(b) - 1; i > 0; i-- {
+		if utf8.RuneStart(b[i]) {
+			return i
+		}
+	}
+	return 0
+}
+
+// StreamDecoder decodes a sequence of IDs received one at a time, such as the
+// output of a text generation model, emitting the decoded text incrementally.
+//
+// Each ID is decoded together with the previous ones, so that the Decoder
+// can take the context into account (for example, WordPiece continuing
+// subwords, or Metaspace leading whitespaces), and only the text which was
+// not emitted yet is returned. Nothing is emitted while the decoded text
+// ends with an incomplete UTF-8 sequence, such as a character split across
+// multiple byte-level tokens.
+type StreamDecoder struct {
+	tokenizer *Tokenizer
+	// IDs which are still needed as context.
+	ids []int
+	// Text decoded from ids[:prefixIndex], which was already emitted.
+	prefix      string
+	prefixIndex int
+}
+
+// NewStreamDecoder returns a new StreamDecoder.
+func (t *Tokenizer) NewStreamDecoder() *StreamDecoder {
+	return &StreamDecoder{
+		tokenizer: t,
+	}
+}
+
+// Step decodes the next ID, and returns the new text, which is empty if
+// more IDs are needed to produce valid text.
+func (s *StreamDecoder) Step(id int) (string, error) {
+	s.ids = append(s.ids, id)
+	text, err := s.tokenizer.Decode(s.ids)
+	if err != nil {
+		s.ids = s.ids[:len(s.ids)-1]
+		return "", err
+	}
+	if len(text) <= len(s.prefix) || strings.HasSuffix(text, string(unicode.ReplacementChar)) {
+		return "", nil
+	}
+	if !strings.HasPrefix(text, s.prefix) {
+		return "", fmt.Errorf("decoded text %q does not start with the previously decoded text %q", text, s.prefix)
+	}
+
+	delta := text[len(s.prefix):]
+	newPrefixIndex := len(s.ids) - s.prefixIndex
+	s.ids = append(s.ids[:0], s.ids[s.prefixIndex:]...)
+	if s.prefix, err = s.tokenizer.Decode(s.ids); err != nil {
+		return "", err
+	}
+	s.prefixIndex = newPrefixIndex
+	return delta, nil
+}
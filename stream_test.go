@@ -0,0 +1,257 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gotokenizers
+
+import (
+	"bytes"
+	"errors"
+	"github.com/nlpodyssey/gotokenizers/encodings"
+	"github.com/nlpodyssey/gotokenizers/models/bpemodel"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/bytelevelpretokenizer"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"github.com/nlpodyssey/gotokenizers/vocabulary"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+)
+
+func TestStreamEncoder(t *testing.T) {
+	t.Parallel()
+
+	text := strings.Repeat("Hello, y'all!  How are   you 😁 ?\n\tCafé 日本語 unaffable\n", 20)
+
+	for _, name := range []string{"bert", "gpt2", "roberta", "metaspace"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tokenizer, err := FromFile(filepath.Join("testdata", "conformance", name, "tokenizer.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := tokenizer.Encode(text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, chunkSize := range []int{4, 7, 64, 0} {
+				se := tokenizer.NewStreamEncoder(iotest.OneByteReader(strings.NewReader(text)), chunkSize)
+				actual := encodings.NewDefaultEncoding()
+				chunks := 0
+				for se.Scan() {
+					actual.MergeWith(se.Encoding(), false)
+					chunks++
+				}
+				if err := se.Err(); err != nil {
+					t.Fatal(err)
+				}
+				if se.Encoding() != nil {
+					t.Errorf("chunk size %d: expected nil Encoding after the end of the stream", chunkSize)
+				}
+				if chunkSize > 0 && chunkSize < 64 && chunks < 2 {
+					t.Errorf("chunk size %d: expected multiple chunks, got %d", chunkSize, chunks)
+				}
+				assertEqual(t, actual.IDs, expected.IDs)
+				assertEqual(t, actual.Tokens, expected.Tokens)
+				assertEqual(t, actual.Words, expected.Words)
+				assertEqual(t, actual.Offsets, expected.Offsets)
+			}
+		})
+	}
+}
+
+func TestStreamEncoderTrimmedOffsets(t *testing.T) {
+	t.Parallel()
+
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "conformance", "roberta", "tokenizer.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = bytes.ReplaceAll(data, []byte(`"trim_offsets": false`), []byte(`"trim_offsets": true`))
+	tokenizer, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := strings.Repeat("Hello world!  Hello, world\n", 10)
+	expected, err := tokenizer.Encode(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, expected.Offsets[:3], []strutils.ByteOffsets{
+		{Start: 0, End: 5},
+		{Start: 6, End: 11},
+		{Start: 11, End: 12},
+	})
+
+	se := tokenizer.NewStreamEncoder(strings.NewReader(text), 8)
+	actual := encodings.NewDefaultEncoding()
+	for se.Scan() {
+		actual.MergeWith(se.Encoding(), false)
+	}
+	if err := se.Err(); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, actual.Tokens, expected.Tokens)
+	assertEqual(t, actual.Offsets, expected.Offsets)
+}
+
+func TestStreamEncoderWithoutWordBoundaries(t *testing.T) {
+	t.Parallel()
+
+	tokenizer := newTestBertTokenizer()
+	text := strings.Repeat("日本語", 10)
+	se := tokenizer.NewStreamEncoder(strings.NewReader(text), 4)
+
+	var offsets []strutils.ByteOffsets
+	for se.Scan() {
+		offsets = append(offsets, se.Encoding().Offsets...)
+	}
+	if err := se.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, len(offsets), 30)
+	for i, o := range offsets {
+		assertEqual(t, o, strutils.ByteOffsets{Start: i * 3, End: i*3 + 3})
+	}
+}
+
+func TestStreamEncoderReadError(t *testing.T) {
+	t.Parallel()
+
+	readErr := errors.New("read error")
+	r := io.MultiReader(strings.NewReader("hello world "), iotest.ErrReader(readErr))
+	se := newTestBertTokenizer().NewStreamEncoder(r, 4)
+
+	for se.Scan() {
+	}
+	if !errors.Is(se.Err(), readErr) {
+		t.Errorf("expected %v, actual %v", readErr, se.Err())
+	}
+	if se.Scan() {
+		t.Error("expected Scan to return false after an error")
+	}
+}
+
+func TestStreamDecoder(t *testing.T) {
+	t.Parallel()
+
+	inputs := []string{
+		"Hello world!",
+		"Hello, y'all!  How are   you 😁 ?",
+		"Café 日本語 unaffable",
+	}
+
+	for _, name := range []string{"bert", "gpt2", "roberta", "metaspace"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tokenizer, err := FromFile(filepath.Join("testdata", "conformance", name, "tokenizer.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, input := range inputs {
+				encoding, err := tokenizer.Encode(input)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expected, err := tokenizer.Decode(encoding.IDs)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				sd := tokenizer.NewStreamDecoder()
+				var sb strings.Builder
+				for _, id := range encoding.IDs {
+					delta, err := sd.Step(id)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !utf8.ValidString(delta) || strings.ContainsRune(delta, utf8.RuneError) {
+						t.Errorf("%q: invalid delta %q", input, delta)
+					}
+					sb.WriteString(delta)
+				}
+				assertEqual(t, sb.String(), expected)
+			}
+		})
+	}
+}
+
+func TestStreamDecoderByteLevel(t *testing.T) {
+	t.Parallel()
+
+	vocab := vocabulary.NewVocabulary()
+	// "😁" is encoded in UTF-8 as 0xF0 0x9F 0x98 0x81, which are mapped
+	// to four distinct byte-level runes
+	for _, term := range []string{"Ġ", "a", "ð", "Ł", "ĺ", "ģ"} {
+		vocab.AddTerm(term)
+	}
+	byteLevel := bytelevelpretokenizer.NewDefault()
+	tokenizer := NewTokenizer(nil, byteLevel, bpemodel.New(vocab, bpemodel.NewMergeMap(),
+		0, 0, "", "", "", false), byteLevel)
+
+	encoding, err := tokenizer.Encode("a😁")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, encoding.Tokens, []string{"Ġ", "a", "ð", "Ł", "ĺ", "ģ"})
+
+	sd := tokenizer.NewStreamDecoder()
+	var deltas []string
+	for _, id := range encoding.IDs {
+		delta, err := sd.Step(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		deltas = append(deltas, delta)
+	}
+	assertEqual(t, deltas, []string{" ", "a", "", "", "", "😁"})
+
+	// Without the prefix space, the decoding starts in the middle of a
+	// character
+	byteLevel = bytelevelpretokenizer.New(bytelevelpretokenizer.DefaultSplittingRegexp, false, true)
+	tokenizer = NewTokenizer(nil, byteLevel, tokenizer.Model(), byteLevel)
+
+	encoding, err = tokenizer.Encode("😁")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, encoding.Tokens, []string{"ð", "Ł", "ĺ", "ģ"})
+
+	sd = tokenizer.NewStreamDecoder()
+	deltas = nil
+	for _, id := range encoding.IDs {
+		delta, err := sd.Step(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		deltas = append(deltas, delta)
+	}
+	assertEqual(t, deltas, []string{"", "", "", "😁"})
+}
+
+func TestStreamDecoderUnknownID(t *testing.T) {
+	t.Parallel()
+
+	sd := newTestBertTokenizer().NewStreamDecoder()
+	if _, err := sd.Step(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sd.Step(1000); err == nil {
+		t.Error("expected an error for an unknown ID")
+	}
+	delta, err := sd.Step(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, delta, " world")
+}
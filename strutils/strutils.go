@@ -4,6 +4,12 @@
 
 package strutils
 
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
 // IsRuneBoundary checks that `index`-th byte is the first byte in a UTF-8 code
 // point sequence or the end of the string.
 //
@@ -38,7 +44,7 @@ type ByteOffsets struct {
 	End int
 }
 
-// RuneOffsets represents a (start, end) range of byte rune positions.
+// RuneOffsets represents a (start, end) range of rune positions.
 type RuneOffsets struct {
 	// Start rune position, inclusive.
 	Start int
@@ -46,33 +52,124 @@ type RuneOffsets struct {
 	End int
 }
 
+// UTF16Offsets represents a (start, end) range of UTF-16 code unit positions.
+type UTF16Offsets struct {
+	// Start UTF-16 code unit position, inclusive.
+	Start int
+	// End UTF-16 code unit position, exclusive.
+	End int
+}
+
+// BytesToRuneOffsetConverter converts ByteOffsets of a specific string into
+// the corresponding RuneOffsets.
 type BytesToRuneOffsetConverter struct {
-	mapping []int
+	c *unitsOffsetConverter
 }
 
+// NewBytesToRuneOffsetConverter returns a new BytesToRuneOffsetConverter
+// for the given sequence.
 func NewBytesToRuneOffsetConverter(sequence string) *BytesToRuneOffsetConverter {
-	mapping := make([]int, 0, len(sequence))
-	for runeIndex, r := range sequence {
-		for range []byte(string(r)) {
-			mapping = append(mapping, runeIndex)
-		}
+	return &BytesToRuneOffsetConverter{
+		c: newUnitsOffsetConverter(sequence, func(rune) int { return 1 }),
 	}
-	return &BytesToRuneOffsetConverter{mapping: mapping}
 }
 
-func (b *BytesToRuneOffsetConverter) Convert(offsets ByteOffsets) RuneOffsets {
-	if len(b.mapping) == 0 {
-		return RuneOffsets{Start: 0, End: 0}
+// Convert converts the given ByteOffsets to RuneOffsets.
+//
+// Offsets pointing inside a multi-byte rune are expanded to include the
+// whole rune. An error is returned if the offsets are inverted, or out of
+// the bounds of the string.
+func (b *BytesToRuneOffsetConverter) Convert(offsets ByteOffsets) (RuneOffsets, error) {
+	start, end, err := b.c.convert(offsets)
+	if err != nil {
+		return RuneOffsets{}, err
 	}
+	return RuneOffsets{Start: start, End: end}, nil
+}
 
-	start := b.mapping[offsets.Start]
+// BytesToUTF16OffsetConverter converts ByteOffsets of a specific string into
+// the corresponding UTF16Offsets.
+//
+// Runes outside the Basic Multilingual Plane (such as most emoji) are
+// encoded as a surrogate pair, that is two UTF-16 code units.
+type BytesToUTF16OffsetConverter struct {
+	c *unitsOffsetConverter
+}
 
-	var end int
-	if offsets.End == len(b.mapping) {
-		end = b.mapping[offsets.End-1] + 1
-	} else {
-		end = b.mapping[offsets.End]
+// NewBytesToUTF16OffsetConverter returns a new BytesToUTF16OffsetConverter
+// for the given sequence.
+func NewBytesToUTF16OffsetConverter(sequence string) *BytesToUTF16OffsetConverter {
+	return &BytesToUTF16OffsetConverter{
+		c: newUnitsOffsetConverter(sequence, utf16Len),
 	}
+}
+
+// Convert converts the given ByteOffsets to UTF16Offsets.
+//
+// Offsets pointing inside a multi-byte rune are expanded to include the
+// whole rune. An error is returned if the offsets are inverted, or out of
+// the bounds of the string.
+func (b *BytesToUTF16OffsetConverter) Convert(offsets ByteOffsets) (UTF16Offsets, error) {
+	start, end, err := b.c.convert(offsets)
+	if err != nil {
+		return UTF16Offsets{}, err
+	}
+	return UTF16Offsets{Start: start, End: end}, nil
+}
 
-	return RuneOffsets{Start: start, End: end}
+// unitsOffsetConverter converts byte positions of a string into positions
+// expressed in some other unit (such as runes or UTF-16 code units).
+type unitsOffsetConverter struct {
+	// For each byte of the string, plus the end of the string, the position
+	// of the first unit of the rune containing it.
+	startMapping []int
+	// For each byte of the string, the position right after the last unit
+	// of the rune containing it.
+	endMapping []int
+}
+
+func newUnitsOffsetConverter(s string, unitsLen func(rune) int) *unitsOffsetConverter {
+	startMapping := make([]int, 0, len(s)+1)
+	endMapping := make([]int, 0, len(s))
+
+	unitIndex := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		nextUnitIndex := unitIndex + unitsLen(r)
+		for j := 0; j < size; j++ {
+			startMapping = append(startMapping, unitIndex)
+			endMapping = append(endMapping, nextUnitIndex)
+		}
+		unitIndex = nextUnitIndex
+		i += size
+	}
+	startMapping = append(startMapping, unitIndex)
+
+	return &unitsOffsetConverter{
+		startMapping: startMapping,
+		endMapping:   endMapping,
+	}
+}
+
+func (c *unitsOffsetConverter) convert(offsets ByteOffsets) (start, end int, err error) {
+	if offsets.Start < 0 || offsets.Start > offsets.End || offsets.End > len(c.endMapping) {
+		return 0, 0, fmt.Errorf("invalid byte offsets [%d, %d) for a string of %d bytes",
+			offsets.Start, offsets.End, len(c.endMapping))
+	}
+	start = c.startMapping[offsets.Start]
+	if offsets.End == offsets.Start {
+		return start, start, nil
+	}
+	// The unit containing the last byte of the range is included
+	end = c.endMapping[offsets.End-1]
+	return start, end, nil
+}
+
+// utf16Len returns the number of UTF-16 code units needed to encode the
+// given rune.
+func utf16Len(r rune) int {
+	if r >= 0x10000 && r <= unicode.MaxRune {
+		return 2
+	}
+	return 1
 }
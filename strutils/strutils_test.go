@@ -34,3 +34,74 @@ func assertEqual(t *testing.T, actual, expected interface{}) {
 		t.Errorf("expected\n  %#v\nactual\n  %#v", expected, actual)
 	}
 }
+
+func TestBytesToRuneOffsetConverter(t *testing.T) {
+	t.Parallel()
+
+	// "e" + combining acute accent, then an emoji ZWJ sequence (woman + ZWJ + laptop)
+	s := "Cafe\u0301 \U0001F469\u200D\U0001F4BB!"
+	c := NewBytesToRuneOffsetConverter(s)
+	convert := func(offsets ByteOffsets) RuneOffsets {
+		t.Helper()
+		r, err := c.Convert(offsets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	assertEqual(t, convert(ByteOffsets{Start: 0, End: 0}), RuneOffsets{Start: 0, End: 0})
+	assertEqual(t, convert(ByteOffsets{Start: 0, End: 6}), RuneOffsets{Start: 0, End: 5})
+	assertEqual(t, convert(ByteOffsets{Start: 4, End: 6}), RuneOffsets{Start: 4, End: 5})
+	assertEqual(t, convert(ByteOffsets{Start: 7, End: 18}), RuneOffsets{Start: 6, End: 9})
+	assertEqual(t, convert(ByteOffsets{Start: 18, End: 19}), RuneOffsets{Start: 9, End: 10})
+	assertEqual(t, convert(ByteOffsets{Start: 0, End: len(s)}), RuneOffsets{Start: 0, End: 10})
+	assertEqual(t, convert(ByteOffsets{Start: len(s), End: len(s)}), RuneOffsets{Start: 10, End: 10})
+
+	// Offsets inside a multi-byte rune are expanded to the whole rune
+	assertEqual(t, convert(ByteOffsets{Start: 8, End: 9}), RuneOffsets{Start: 6, End: 7})
+
+	empty, err := NewBytesToRuneOffsetConverter("").Convert(ByteOffsets{Start: 0, End: 0})
+	assertEqual(t, err, nil)
+	assertEqual(t, empty, RuneOffsets{Start: 0, End: 0})
+
+	for _, invalid := range []ByteOffsets{
+		{Start: -1, End: 2},
+		{Start: 3, End: 2},
+		{Start: 0, End: len(s) + 1},
+		{Start: len(s) + 1, End: len(s) + 1},
+	} {
+		if _, err := c.Convert(invalid); err == nil {
+			t.Errorf("expected an error for %v", invalid)
+		}
+	}
+}
+
+func TestBytesToUTF16OffsetConverter(t *testing.T) {
+	t.Parallel()
+
+	s := "Cafe\u0301 \U0001F469\u200D\U0001F4BB!"
+	c := NewBytesToUTF16OffsetConverter(s)
+	convert := func(offsets ByteOffsets) UTF16Offsets {
+		t.Helper()
+		r, err := c.Convert(offsets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	assertEqual(t, convert(ByteOffsets{Start: 0, End: 0}), UTF16Offsets{Start: 0, End: 0})
+	assertEqual(t, convert(ByteOffsets{Start: 0, End: 6}), UTF16Offsets{Start: 0, End: 5})
+	assertEqual(t, convert(ByteOffsets{Start: 7, End: 11}), UTF16Offsets{Start: 6, End: 8})
+	assertEqual(t, convert(ByteOffsets{Start: 7, End: 18}), UTF16Offsets{Start: 6, End: 11})
+	assertEqual(t, convert(ByteOffsets{Start: 18, End: 19}), UTF16Offsets{Start: 11, End: 12})
+	assertEqual(t, convert(ByteOffsets{Start: 0, End: len(s)}), UTF16Offsets{Start: 0, End: 12})
+
+	// Offsets inside a multi-byte rune are expanded to the whole rune
+	assertEqual(t, convert(ByteOffsets{Start: 8, End: 9}), UTF16Offsets{Start: 6, End: 8})
+
+	if _, err := c.Convert(ByteOffsets{Start: 0, End: len(s) + 1}); err == nil {
+		t.Error("expected an error for out of range offsets")
+	}
+}
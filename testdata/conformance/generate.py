@@ -0,0 +1,59 @@
+#!/usr/bin/env python3
+# Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+# Use of this source code is governed by a BSD-style
+# license that can be found in the LICENSE file.
+
+"""Regenerates the conformance fixtures using the Python tokenizers library.
+
+Each sub-directory contains a tokenizer.json file and a fixtures.json file.
+The inputs of the existing fixtures are encoded again, without special
+tokens, and the expected outputs are overwritten. The output of this script
+is meant to be committed unchanged.
+
+To add a new case, create a sub-directory with its tokenizer.json and a
+fixtures.json listing only the inputs, e.g. [{"input": "Hello world"}].
+
+Usage:
+
+    pip install tokenizers==0.19.1
+    python3 testdata/conformance/generate.py
+"""
+
+import json
+import os
+import sys
+
+import tokenizers
+from tokenizers import Tokenizer
+
+# The fixtures are pinned to this version of the tokenizers library.
+TOKENIZERS_VERSION = "0.19.1"
+
+ROOT = os.path.dirname(os.path.abspath(__file__))
+
+
+def main():
+    if tokenizers.__version__ != TOKENIZERS_VERSION:
+        sys.exit("tokenizers %s is required, found %s"
+                 % (TOKENIZERS_VERSION, tokenizers.__version__))
+    for name in sorted(os.listdir(ROOT)):
+        directory = os.path.join(ROOT, name)
+        if not os.path.isdir(directory):
+            continue
+        tokenizer = Tokenizer.from_file(os.path.join(directory, "tokenizer.json"))
+        fixtures_path = os.path.join(directory, "fixtures.json")
+        with open(fixtures_path, encoding="utf-8") as f:
+            fixtures = json.load(f)
+        for fixture in fixtures:
+            encoding = tokenizer.encode(fixture["input"], add_special_tokens=False)
+            fixture["ids"] = encoding.ids
+            fixture["tokens"] = encoding.tokens
+            fixture["offsets"] = [list(o) for o in encoding.offsets]
+            fixture["word_ids"] = encoding.word_ids
+        with open(fixtures_path, "w", encoding="utf-8") as f:
+            json.dump(fixtures, f, ensure_ascii=False, indent=2)
+            f.write("\n")
+
+
+if __name__ == "__main__":
+    main()
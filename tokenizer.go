@@ -0,0 +1,295 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gotokenizers
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/gotokenizers/decoders"
+	"github.com/nlpodyssey/gotokenizers/encodings"
+	"github.com/nlpodyssey/gotokenizers/models"
+	"github.com/nlpodyssey/gotokenizers/normalizedstring"
+	"github.com/nlpodyssey/gotokenizers/normalizers"
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"strings"
+)
+
+// Tokenizer represents a full tokenization pipeline, made of an optional
+// Normalizer, an optional PreTokenizer, and a Model. An optional Decoder
+// can be used to convert IDs back into text.
+type Tokenizer struct {
+	normalizer   normalizers.Normalizer
+	preTokenizer pretokenizers.PreTokenizer
+	model        models.Model
+	decoder      decoders.Decoder
+}
+
+// NewTokenizer returns a new Tokenizer.
+//
+// The normalizer, the preTokenizer and the decoder are optional, and can be
+// set to nil.
+func NewTokenizer(
+	normalizer normalizers.Normalizer,
+	preTokenizer pretokenizers.PreTokenizer,
+	model models.Model,
+	decoder decoders.Decoder,
+) *Tokenizer {
+	return &Tokenizer{
+		normalizer:   normalizer,
+		preTokenizer: preTokenizer,
+		model:        model,
+		decoder:      decoder,
+	}
+}
+
+// Normalizer returns the Normalizer of the Tokenizer, or nil.
+func (t *Tokenizer) Normalizer() normalizers.Normalizer {
+	return t.normalizer
+}
+
+// PreTokenizer returns the PreTokenizer of the Tokenizer, or nil.
+func (t *Tokenizer) PreTokenizer() pretokenizers.PreTokenizer {
+	return t.preTokenizer
+}
+
+// Model returns the Model of the Tokenizer.
+func (t *Tokenizer) Model() models.Model {
+	return t.model
+}
+
+// Decoder returns the Decoder of the Tokenizer, or nil.
+func (t *Tokenizer) Decoder() decoders.Decoder {
+	return t.decoder
+}
+
+// PretokenizedWordsSeparator is the string which is virtually placed between
+// the words given to Tokenizer.EncodePretokenized, for the computation of
+// the offsets.
+const PretokenizedWordsSeparator = " "
+
+// Encode encodes the given sequence.
+//
+// The offsets of the resulting Encoding are byte positions relative to the
+// given sequence.
+func (t *Tokenizer) Encode(sequence string) (*encodings.Encoding, error) {
+	return t.encode(normalizedstring.FromString(sequence), -1, nil)
+}
+
+// EncodeWithoutOffsets encodes the given sequence, like Encode, without
+// keeping track of the alignments between the original and the normalized
+// string.
+//
+// It is faster and needs less memory than Encode, especially on long
+// sequences, but the offsets of the resulting Encoding are all zero.
+func (t *Tokenizer) EncodeWithoutOffsets(sequence string) (*encodings.Encoding, error) {
+	return t.encode(normalizedstring.FromStringWithoutAlignments(sequence), -1, nil)
+}
+
+// EncodePretokenized encodes a sequence which is already split into words.
+//
+// Each word is normalized, pre-tokenized and tokenized separately, and all
+// its tokens get the word's index in the Encoding.Words. The offsets are
+// byte positions relative to the string obtained by joining the words
+// with PretokenizedWordsSeparator.
+func (t *Tokenizer) EncodePretokenized(words []string) (*encodings.Encoding, error) {
+	encoding := encodings.NewDefaultEncoding()
+	offset := 0
+	for wordIndex, word := range words {
+		wordEncoding, err := t.encode(normalizedstring.FromString(word), wordIndex, nil)
+		if err != nil {
+			return nil, err
+		}
+		for i, o := range wordEncoding.Offsets {
+			wordEncoding.Offsets[i] = strutils.ByteOffsets{
+				Start: o.Start + offset,
+				End:   o.End + offset,
+			}
+		}
+		encoding.MergeWith(wordEncoding, false)
+		offset += len(word) + len(PretokenizedWordsSeparator)
+	}
+	return encoding, nil
+}
+
+// Count returns the number of tokens the given sequence is encoded into,
+// that is the same as len(Encode(sequence).IDs).
+//
+// It is faster than Encode, since the alignments are not tracked (see
+// EncodeWithoutOffsets), no Encoding is built, and, if the Model implements
+// models.Counter, the tokens are only counted.
+func (t *Tokenizer) Count(sequence string) (int, error) {
+	ns := normalizedstring.FromStringWithoutAlignments(sequence)
+	if err := t.normalize(ns, nil); err != nil {
+		return 0, err
+	}
+
+	pts := pretokenizedstring.FromNormalizedString(ns)
+	if err := t.preTokenize(pts, nil); err != nil {
+		return 0, err
+	}
+
+	counter, isCounter := t.model.(models.Counter)
+	count := 0
+	for _, split := range pts.Splits() {
+		if split.Tokens != nil {
+			count += len(*split.Tokens)
+			continue
+		}
+		s := split.NormalizedString.Get()
+		if isCounter {
+			n, err := counter.CountTokens(s)
+			if err != nil {
+				return 0, err
+			}
+			count += n
+			continue
+		}
+		tokens, err := t.model.Tokenize(s)
+		if err != nil {
+			return 0, err
+		}
+		count += len(tokens)
+	}
+	return count, nil
+}
+
+// Decode converts the given IDs back into text.
+//
+// The IDs are first converted to tokens using the Model, which must
+// implement models.Vocabularied, and the tokens are then processed by the
+// Decoder. If the Tokenizer has no Decoder, the tokens are simply joined
+// with whitespaces.
+//
+// If an ID is not found in the vocabulary, the returned error is a
+// *models.UnknownIDError.
+func (t *Tokenizer) Decode(ids []int) (string, error) {
+	vocabularied, ok := t.model.(models.Vocabularied)
+	if !ok {
+		return "", fmt.Errorf("model %T cannot convert IDs to tokens", t.model)
+	}
+	tokens := make([]string, len(ids))
+	for i, id := range ids {
+		token, ok := vocabularied.IDToToken(id)
+		if !ok {
+			return "", &models.UnknownIDError{ID: id}
+		}
+		tokens[i] = token
+	}
+
+	if t.decoder == nil {
+		return strings.Join(tokens, " "), nil
+	}
+	return decoders.Decode(t.decoder, tokens)
+}
+
+// encode runs the whole pipeline over the given NormalizedString. The
+// wordIndex is passed to PreTokenizedString.IntoEncoding: it is -1 for
+// a whole sequence, otherwise the index of a pre-tokenized word, which
+// starts the sequence only if it is the first one.
+//
+// If trace is not nil, the output of each step is recorded.
+//
+// The offsets are not processed if the alignments of the NormalizedString
+// are not tracked, since they are all zero.
+func (t *Tokenizer) encode(
+	ns *normalizedstring.NormalizedString,
+	wordIndex int,
+	trace *Trace,
+) (*encodings.Encoding, error) {
+	alignmentsTracked := ns.AlignmentsTracked()
+	pts, err := t.tokenize(ns, trace)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding, err := pts.IntoEncoding(wordIndex, 0)
+	if err != nil {
+		return nil, err
+	}
+	if alignmentsTracked {
+		t.processOffsets(encoding, wordIndex <= 0)
+	}
+
+	if trace != nil {
+		trace.Tokenization = pts.GetOriginalByteSplits()
+		trace.Encoding = encoding
+	}
+	return encoding, nil
+}
+
+// tokenize normalizes, pre-tokenizes and tokenizes the given NormalizedString.
+//
+// If trace is not nil, the output of the normalization and pre-tokenization
+// steps is recorded.
+func (t *Tokenizer) tokenize(
+	ns *normalizedstring.NormalizedString,
+	trace *Trace,
+) (*pretokenizedstring.PreTokenizedString, error) {
+	if err := t.normalize(ns, trace); err != nil {
+		return nil, err
+	}
+
+	pts := pretokenizedstring.FromNormalizedString(ns)
+	if err := t.preTokenize(pts, trace); err != nil {
+		return nil, err
+	}
+
+	err := pts.Tokenize(func(ns *normalizedstring.NormalizedString) ([]models.Token, error) {
+		return t.model.Tokenize(ns.Get())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pts, nil
+}
+
+// processOffsets lets the PreTokenizers implementing
+// pretokenizers.OffsetsProcessor adjust the offsets of the Encoding.
+func (t *Tokenizer) processOffsets(encoding *encodings.Encoding, sequenceStart bool) {
+	if t.preTokenizer == nil {
+		return
+	}
+	for _, preTokenizer := range flattenPreTokenizers(t.preTokenizer) {
+		if p, ok := preTokenizer.(pretokenizers.OffsetsProcessor); ok {
+			p.ProcessOffsets(encoding, sequenceStart)
+		}
+	}
+}
+
+func (t *Tokenizer) normalize(ns *normalizedstring.NormalizedString, trace *Trace) error {
+	if t.normalizer == nil {
+		return nil
+	}
+	if trace == nil {
+		return t.normalizer.Normalize(ns)
+	}
+	for _, normalizer := range flattenNormalizers(t.normalizer) {
+		if err := normalizer.Normalize(ns); err != nil {
+			return err
+		}
+		trace.Normalization = append(trace.Normalization, newNormalizationStep(normalizer, ns))
+	}
+	return nil
+}
+
+func (t *Tokenizer) preTokenize(pts *pretokenizedstring.PreTokenizedString, trace *Trace) error {
+	if t.preTokenizer == nil {
+		return nil
+	}
+	if trace == nil {
+		return t.preTokenizer.PreTokenize(pts)
+	}
+	for _, preTokenizer := range flattenPreTokenizers(t.preTokenizer) {
+		if err := preTokenizer.PreTokenize(pts); err != nil {
+			return err
+		}
+		trace.PreTokenization = append(trace.PreTokenization, PreTokenizationStep{
+			PreTokenizer: fmt.Sprintf("%T", preTokenizer),
+			Splits:       pts.GetOriginalByteSplits(),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gotokenizers
+
+import (
+	"errors"
+	"github.com/nlpodyssey/gotokenizers/decoders/wordpiecedecoder"
+	"github.com/nlpodyssey/gotokenizers/encodings"
+	"github.com/nlpodyssey/gotokenizers/models"
+	"github.com/nlpodyssey/gotokenizers/models/wordpiecemodel"
+	"github.com/nlpodyssey/gotokenizers/normalizers/bertnormalizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/bertpretokenizer"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"github.com/nlpodyssey/gotokenizers/vocabulary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestBertTokenizer() *Tokenizer {
+	terms := []string{
+		"[UNK]",  // 0
+		"hello",  // 1
+		"world",  // 2
+		"un",     // 3
+		"##aff",  // 4
+		"##able", // 5
+		"!",      // 6
+		"new",    // 7
+		"york",   // 8
+	}
+	vocab := vocabulary.NewVocabulary()
+	for _, term := range terms {
+		vocab.AddTerm(term)
+	}
+	return NewTokenizer(
+		bertnormalizer.DefaultBertNormalizer(),
+		bertpretokenizer.New(),
+		wordpiecemodel.New(vocab, "[UNK]", "##", 100),
+		wordpiecedecoder.NewDefault(),
+	)
+}
+
+func TestTokenizerEncode(t *testing.T) {
+	t.Parallel()
+
+	tokenizer := newTestBertTokenizer()
+	encoding, err := tokenizer.Encode("Hello unaffable world!")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, encoding.IDs, []int{1, 3, 4, 5, 2, 6})
+	assertEqual(t, encoding.Tokens, []string{"hello", "un", "##aff", "##able", "world", "!"})
+	assertEqual(t, encoding.Words, []int{0, 1, 1, 1, 2, 3})
+	assertEqual(t, encoding.Offsets, []strutils.ByteOffsets{
+		{Start: 0, End: 5},
+		{Start: 6, End: 8},
+		{Start: 8, End: 11},
+		{Start: 11, End: 15},
+		{Start: 16, End: 21},
+		{Start: 21, End: 22},
+	})
+}
+
+func TestTokenizerEncodePretokenized(t *testing.T) {
+	t.Parallel()
+
+	tokenizer := newTestBertTokenizer()
+
+	t.Run("Basic", func(t *testing.T) {
+		encoding, err := tokenizer.EncodePretokenized([]string{"Hello", "unaffable", "world!"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, encoding.IDs, []int{1, 3, 4, 5, 2, 6})
+		assertEqual(t, encoding.Tokens, []string{"hello", "un", "##aff", "##able", "world", "!"})
+		// "world!" is further split by the pre-tokenizer, but it is still
+		// a single word of the input
+		assertEqual(t, encoding.Words, []int{0, 1, 1, 1, 2, 2})
+		assertEqual(t, encoding.Offsets, []strutils.ByteOffsets{
+			{Start: 0, End: 5},
+			{Start: 6, End: 8},
+			{Start: 8, End: 11},
+			{Start: 11, End: 15},
+			{Start: 16, End: 21},
+			{Start: 21, End: 22},
+		})
+		assertEqual(t, encoding.TypeIDs, []int{0, 0, 0, 0, 0, 0})
+		assertEqual(t, encoding.AttentionMask, []int{1, 1, 1, 1, 1, 1})
+	})
+
+	t.Run("Words with inner whitespace", func(t *testing.T) {
+		encoding, err := tokenizer.EncodePretokenized([]string{"New York", "!"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, encoding.Tokens, []string{"new", "york", "!"})
+		assertEqual(t, encoding.Words, []int{0, 0, 1})
+		assertEqual(t, encoding.Offsets, []strutils.ByteOffsets{
+			{Start: 0, End: 3},
+			{Start: 4, End: 8},
+			{Start: 9, End: 10},
+		})
+	})
+
+	t.Run("Empty words", func(t *testing.T) {
+		encoding, err := tokenizer.EncodePretokenized([]string{"hello", "", "world"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, encoding.Tokens, []string{"hello", "world"})
+		assertEqual(t, encoding.Words, []int{0, 2})
+		assertEqual(t, encoding.Offsets, []strutils.ByteOffsets{
+			{Start: 0, End: 5},
+			{Start: 7, End: 12},
+		})
+	})
+
+	t.Run("Trimmed offsets", func(t *testing.T) {
+		tokenizer, err := FromFile("testdata/conformance/roberta-trim/tokenizer.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoding, err := tokenizer.EncodePretokenized([]string{"Hello", "world", " world"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, encoding.Tokens, []string{"ĠHello", "Ġworld", "Ġworld"})
+		assertEqual(t, encoding.Words, []int{0, 1, 2})
+		// The offsets are trimmed word by word, so the single leading
+		// space of each word is kept, as in Hugging Face's tokenizers
+		// library, where the offsets of pre-tokenized words start at 0.
+		assertEqual(t, encoding.Offsets, []strutils.ByteOffsets{
+			{Start: 0, End: 5},
+			{Start: 6, End: 11},
+			{Start: 12, End: 18},
+		})
+	})
+}
+
+func TestTokenizerEncodeWithoutOffsets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WordPiece", func(t *testing.T) {
+		tokenizer := newTestBertTokenizer()
+		encoding, err := tokenizer.EncodeWithoutOffsets("Hello unaffable world!")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, encoding.IDs, []int{1, 3, 4, 5, 2, 6})
+		assertEqual(t, encoding.Tokens, []string{"hello", "un", "##aff", "##able", "world", "!"})
+		assertEqual(t, encoding.Words, []int{0, 1, 1, 1, 2, 3})
+		assertEqual(t, encoding.Offsets, make([]strutils.ByteOffsets, 6))
+	})
+
+	t.Run("Trimmed offsets", func(t *testing.T) {
+		tokenizer, err := FromFile("testdata/conformance/roberta-trim/tokenizer.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoding, err := tokenizer.EncodeWithoutOffsets("Hello  world ")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, encoding.Tokens, []string{"ĠHello", "Ġ", "Ġworld", "Ġ"})
+		assertEqual(t, encoding.Offsets, make([]strutils.ByteOffsets, 4))
+	})
+}
+
+func TestTokenizerCount(t *testing.T) {
+	t.Parallel()
+
+	tokenizer := newTestBertTokenizer()
+	tokenizeOnly := NewTokenizer(
+		tokenizer.Normalizer(),
+		tokenizer.PreTokenizer(),
+		tokenizeOnlyModel{tokenizer.Model()},
+		nil,
+	)
+
+	for _, sequence := range []string{
+		"",
+		"   ",
+		"Hello unaffable world!",
+		"Hello, New York! Goodbye?",
+	} {
+		encoding, err := tokenizer.Encode(sequence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tk := range []*Tokenizer{tokenizer, tokenizeOnly} {
+			count, err := tk.Count(sequence)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertEqual(t, count, encoding.Len())
+		}
+	}
+}
+
+// tokenizeOnlyModel hides the models.Counter and models.Vocabularied
+// implementations of a Model.
+type tokenizeOnlyModel struct {
+	models.Model
+}
+
+func TestTokenizerDecode(t *testing.T) {
+	t.Parallel()
+
+	tokenizer := newTestBertTokenizer()
+
+	decoded, err := tokenizer.Decode([]int{1, 3, 4, 5, 2, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, decoded, "hello unaffable world!")
+
+	_, err = tokenizer.Decode([]int{1, 42})
+	var unknownIDErr *models.UnknownIDError
+	if !errors.As(err, &unknownIDErr) || unknownIDErr.ID != 42 {
+		t.Errorf("expected *models.UnknownIDError for ID 42, actual %#v", err)
+	}
+
+	// A Model without a vocabulary cannot be used for decoding
+	tokenizer = NewTokenizer(nil, nil, tokenizeOnlyModel{tokenizer.Model()}, nil)
+	if _, err := tokenizer.Decode([]int{1}); err == nil {
+		t.Error("expected an error for a Model not implementing models.Vocabularied")
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected\n  %#v\nactual\n  %#v", expected, actual)
+	}
+}
+
+func BenchmarkTokenizerEncode(b *testing.B) {
+	benchmarkTokenizerEncode(b, (*Tokenizer).Encode)
+}
+
+func BenchmarkTokenizerEncodeWithoutOffsets(b *testing.B) {
+	benchmarkTokenizerEncode(b, (*Tokenizer).EncodeWithoutOffsets)
+}
+
+func BenchmarkTokenizerCount(b *testing.B) {
+	benchmarkTokenizerEncode(b, func(t *Tokenizer, s string) (*encodings.Encoding, error) {
+		_, err := t.Count(s)
+		return nil, err
+	})
+}
+
+func benchmarkTokenizerEncode(
+	b *testing.B,
+	encode func(*Tokenizer, string) (*encodings.Encoding, error),
+) {
+	tokenizer := newTestBertTokenizer()
+	document := strings.Repeat("Hello unaffable world! Hello, New York. ", 2500)
+	b.SetBytes(int64(len(document)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := encode(tokenizer, document); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
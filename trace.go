@@ -0,0 +1,109 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gotokenizers
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/gotokenizers/encodings"
+	"github.com/nlpodyssey/gotokenizers/normalizedstring"
+	"github.com/nlpodyssey/gotokenizers/normalizers"
+	"github.com/nlpodyssey/gotokenizers/normalizers/sequencenormalizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/sequencepretokenizer"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+)
+
+// Trace is a step-by-step report of the encoding of a sequence, mostly
+// useful for debugging.
+//
+// Sequences of normalizers and pre-tokenizers are expanded, so that
+// there is one step for each single component.
+type Trace struct {
+	// The original sequence.
+	Original string
+	// The state of the NormalizedString after each normalizer.
+	Normalization []NormalizationStep
+	// The splits after each pre-tokenizer.
+	PreTokenization []PreTokenizationStep
+	// The final splits, along with the tokens produced by the model.
+	// Token offsets are relative to the "normalized" string of each split.
+	Tokenization []pretokenizedstring.OriginalByteSplit
+	// The resulting Encoding.
+	Encoding *encodings.Encoding
+}
+
+// NormalizationStep is the state of a NormalizedString right after
+// a normalizer has been applied.
+type NormalizationStep struct {
+	// Name of the normalizer's type.
+	Normalizer string
+	// The "normalized" string.
+	Normalized string
+	// For each byte of the "normalized" string, the corresponding range
+	// of the "original" string.
+	Alignments []strutils.ByteOffsets
+}
+
+// PreTokenizationStep reports the splits of a PreTokenizedString right
+// after a pre-tokenizer has been applied.
+type PreTokenizationStep struct {
+	// Name of the pre-tokenizer's type.
+	PreTokenizer string
+	// The splits, with offsets in the original referential.
+	Splits []pretokenizedstring.OriginalByteSplit
+}
+
+// Trace encodes the given sequence, like Encode, additionally recording
+// the output of each step of the pipeline.
+func (t *Tokenizer) Trace(sequence string) (*Trace, error) {
+	trace := &Trace{Original: sequence}
+	if _, err := t.encode(normalizedstring.FromString(sequence), -1, trace); err != nil {
+		return nil, err
+	}
+	return trace, nil
+}
+
+func newNormalizationStep(
+	normalizer normalizers.Normalizer,
+	ns *normalizedstring.NormalizedString,
+) NormalizationStep {
+	alignments := ns.Alignments()
+	offsets := make([]strutils.ByteOffsets, len(alignments))
+	for i, a := range alignments {
+		offsets[i] = strutils.ByteOffsets{Start: a.Start(), End: a.End()}
+	}
+	return NormalizationStep{
+		Normalizer: fmt.Sprintf("%T", normalizer),
+		Normalized: ns.Get(),
+		Alignments: offsets,
+	}
+}
+
+// flattenNormalizers recursively expands sequences of normalizers.
+func flattenNormalizers(n normalizers.Normalizer) []normalizers.Normalizer {
+	seq, ok := n.(*sequencenormalizer.SequenceNormalizer)
+	if !ok {
+		return []normalizers.Normalizer{n}
+	}
+	result := make([]normalizers.Normalizer, 0)
+	for _, item := range seq.Normalizers() {
+		result = append(result, flattenNormalizers(item)...)
+	}
+	return result
+}
+
+// flattenPreTokenizers recursively expands sequences of pre-tokenizers.
+func flattenPreTokenizers(p pretokenizers.PreTokenizer) []pretokenizers.PreTokenizer {
+	seq, ok := p.(*sequencepretokenizer.SequencePreTokenizer)
+	if !ok {
+		return []pretokenizers.PreTokenizer{p}
+	}
+	result := make([]pretokenizers.PreTokenizer, 0)
+	for _, item := range seq.PreTokenizers() {
+		result = append(result, flattenPreTokenizers(item)...)
+	}
+	return result
+}
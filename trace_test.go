@@ -0,0 +1,84 @@
+// Copyright (c) 2020, NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gotokenizers
+
+import (
+	"github.com/nlpodyssey/gotokenizers/models/wordpiecemodel"
+	"github.com/nlpodyssey/gotokenizers/normalizers"
+	"github.com/nlpodyssey/gotokenizers/normalizers/lowercasenormalizer"
+	"github.com/nlpodyssey/gotokenizers/normalizers/sequencenormalizer"
+	"github.com/nlpodyssey/gotokenizers/normalizers/stripnormalizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizedstring"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/digitspretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/sequencepretokenizer"
+	"github.com/nlpodyssey/gotokenizers/pretokenizers/whitespacesplitpretokenizer"
+	"github.com/nlpodyssey/gotokenizers/strutils"
+	"github.com/nlpodyssey/gotokenizers/vocabulary"
+	"testing"
+)
+
+func TestTokenizerTrace(t *testing.T) {
+	t.Parallel()
+
+	vocab := vocabulary.NewVocabulary()
+	for _, term := range []string{"[UNK]", "hi", "ab", "1", "2"} {
+		vocab.AddTerm(term)
+	}
+	tokenizer := NewTokenizer(
+		sequencenormalizer.NewSequenceNormalizer([]normalizers.Normalizer{
+			stripnormalizer.NewStripNormalizer(true, true),
+			lowercasenormalizer.NewLowerCaseNormalizer(),
+		}),
+		sequencepretokenizer.New([]pretokenizers.PreTokenizer{
+			whitespacesplitpretokenizer.New(),
+			digitspretokenizer.New(true),
+		}),
+		wordpiecemodel.New(vocab, "[UNK]", "##", 100),
+		nil,
+	)
+
+	trace, err := tokenizer.Trace(" Hi AB12 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, trace.Original, " Hi AB12 ")
+
+	assertEqual(t, len(trace.Normalization), 2)
+	assertEqual(t, trace.Normalization[0].Normalizer, "*stripnormalizer.StripNormalizer")
+	assertEqual(t, trace.Normalization[0].Normalized, "Hi AB12")
+	assertEqual(t, trace.Normalization[1].Normalizer, "*lowercasenormalizer.LowerCaseNormalizer")
+	assertEqual(t, trace.Normalization[1].Normalized, "hi ab12")
+	assertEqual(t, trace.Normalization[1].Alignments, []strutils.ByteOffsets{
+		{Start: 1, End: 2}, {Start: 2, End: 3}, {Start: 3, End: 4}, {Start: 4, End: 5},
+		{Start: 5, End: 6}, {Start: 6, End: 7}, {Start: 7, End: 8},
+	})
+
+	assertEqual(t, len(trace.PreTokenization), 2)
+	assertEqual(t, trace.PreTokenization[0].PreTokenizer,
+		"*whitespacesplitpretokenizer.WhiteSpaceSplitPreTokenizer")
+	assertEqual(t, splitStrings(trace.PreTokenization[0].Splits), []string{"hi", "ab12"})
+	assertEqual(t, trace.PreTokenization[1].PreTokenizer, "*digitspretokenizer.DigitsPreTokenizer")
+	assertEqual(t, splitStrings(trace.PreTokenization[1].Splits), []string{"hi", "ab", "1", "2"})
+	assertEqual(t, trace.PreTokenization[1].Splits[3].Offsets, strutils.ByteOffsets{Start: 7, End: 8})
+
+	assertEqual(t, splitStrings(trace.Tokenization), []string{"hi", "ab", "1", "2"})
+	assertEqual(t, trace.Encoding.IDs, []int{1, 2, 3, 4})
+	assertEqual(t, trace.Encoding.Offsets, []strutils.ByteOffsets{
+		{Start: 1, End: 3},
+		{Start: 4, End: 6},
+		{Start: 6, End: 7},
+		{Start: 7, End: 8},
+	})
+}
+
+func splitStrings(splits []pretokenizedstring.OriginalByteSplit) []string {
+	result := make([]string, len(splits))
+	for i, split := range splits {
+		result[i] = split.String
+	}
+	return result
+}
@@ -5,14 +5,22 @@
 package vocabulary
 
 import (
+	"bufio"
 	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"sort"
+	"strings"
 )
 
 // Vocabulary stores ID-term bidirectional associations.
 type Vocabulary struct {
 	termToID map[string]int
 	idToTerm map[int]string
+	// The ID assigned by AddTerm to the next new term, that is the
+	// greatest ID plus one.
+	nextID int
 }
 
 // NewVocabulary returns a new empty vocabulary.
@@ -23,7 +31,27 @@ func NewVocabulary() *Vocabulary {
 	}
 }
 
+// FromMap returns a new vocabulary with the given term-to-ID associations.
+// The map is copied, so that it can be modified afterwards without
+// affecting the vocabulary.
+//
+// If more terms are associated to the same ID, GetString returns any one
+// of them. CheckDuplicateIDs or Validate can be used to detect this
+// situation.
+func FromMap(termToID map[string]int) *Vocabulary {
+	v := &Vocabulary{
+		termToID: make(map[string]int, len(termToID)),
+		idToTerm: make(map[int]string, len(termToID)),
+	}
+	for term, id := range termToID {
+		v.add(term, id)
+	}
+	return v
+}
+
 // FromJSONFile reads a vocabulary from JSON file.
+//
+// It returns an error if more terms are associated to the same ID.
 func FromJSONFile(filename string) (*Vocabulary, error) {
 	rawData, err := ioutil.ReadFile(filename)
 	if err != nil {
@@ -36,25 +64,138 @@ func FromJSONFile(filename string) (*Vocabulary, error) {
 		return nil, err
 	}
 
-	idToTerm := make(map[int]string, len(termToID))
-	for term, id := range termToID {
-		idToTerm[id] = term
+	v := FromMap(termToID)
+	if err := v.CheckDuplicateIDs(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
 	}
+	return v, nil
+}
 
-	return &Vocabulary{termToID: termToID, idToTerm: idToTerm}, nil
+// FromJSONFileStrict reads a vocabulary from JSON file, like FromJSONFile,
+// and also returns an error if the IDs are not contiguous (see Validate).
+func FromJSONFileStrict(filename string) (*Vocabulary, error) {
+	v, err := FromJSONFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return v, nil
+}
+
+// FromTxtFile reads a vocabulary from a text file, containing one term per
+// line. The ID of each term is the (zero-based) index of its line.
+//
+// The line break at the end of the file is optional, and an empty file
+// results in an empty vocabulary.
+func FromTxtFile(filename string) (*Vocabulary, error) {
+	rawData, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	data := strings.TrimSuffix(string(rawData), "\n")
+	if len(data) == 0 {
+		return NewVocabulary(), nil
+	}
+	lines := strings.Split(data, "\n")
+	termToID := make(map[string]int, len(lines))
+	for id, line := range lines {
+		termToID[strings.TrimRight(line, "\r")] = id
+	}
+	return FromMap(termToID), nil
+}
+
+// FromTxtFileStrict reads a vocabulary from a text file, like FromTxtFile,
+// and also returns an error if a term is repeated on more lines, which
+// would leave a gap in the IDs.
+func FromTxtFileStrict(filename string) (*Vocabulary, error) {
+	v, err := FromTxtFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return v, nil
 }
 
 // AddTerm adds a new term to the vocabulary.
 // If the term does not yet exist in the vocabulary, a new ID is associated to
-// it, corresponding to the current vocabulary size.
+// it, corresponding to the greatest ID plus one (that is, the current
+// vocabulary size, if the IDs have no gaps).
 // Otherwise, if the string already exists, no insertion is performed.
 func (v *Vocabulary) AddTerm(term string) {
 	if _, ok := v.termToID[term]; ok {
 		return
 	}
-	id := v.Size()
+	v.add(term, v.nextID)
+}
+
+// AddTermWithID adds a new term to the vocabulary, with the given ID.
+//
+// It returns an error if the ID is negative, if the ID is already associated
+// to a different term, or if the term is already associated to a
+// different ID. Adding an existing association has no effect.
+func (v *Vocabulary) AddTermWithID(term string, id int) error {
+	if id < 0 {
+		return fmt.Errorf("invalid negative ID %d for term %q", id, term)
+	}
+	if existingID, ok := v.termToID[term]; ok {
+		if existingID != id {
+			return fmt.Errorf("term %q already has ID %d, cannot assign ID %d", term, existingID, id)
+		}
+		return nil
+	}
+	if existingTerm, ok := v.idToTerm[id]; ok {
+		return fmt.Errorf("ID %d already assigned to term %q, cannot assign it to %q", id, existingTerm, term)
+	}
+	v.add(term, id)
+	return nil
+}
+
+func (v *Vocabulary) add(term string, id int) {
 	v.termToID[term] = id
 	v.idToTerm[id] = term
+	if id >= v.nextID {
+		v.nextID = id + 1
+	}
+}
+
+// RemoveTerm removes a term from the vocabulary, and reports whether it
+// was found.
+//
+// The ID of the removed term is not reused by AddTerm, unless it was the
+// greatest one.
+func (v *Vocabulary) RemoveTerm(term string) bool {
+	id, ok := v.termToID[term]
+	if !ok {
+		return false
+	}
+	delete(v.termToID, term)
+	if v.idToTerm[id] == term {
+		delete(v.idToTerm, id)
+		// Another term might be associated to the same ID, which must
+		// still be reported as assigned (see AddTermWithID)
+		if len(v.termToID) > len(v.idToTerm) {
+			for otherTerm, otherID := range v.termToID {
+				if otherID == id {
+					v.idToTerm[id] = otherTerm
+					break
+				}
+			}
+		}
+	}
+	if id == v.nextID-1 {
+		v.nextID = 0
+		for id := range v.idToTerm {
+			if id >= v.nextID {
+				v.nextID = id + 1
+			}
+		}
+	}
+	return true
 }
 
 // Size returns the size of the Vocabulary.
@@ -75,3 +216,124 @@ func (v *Vocabulary) GetString(id int) (string, bool) {
 	s, ok := v.idToTerm[id]
 	return s, ok
 }
+
+// Range calls f for each ID-term association, sorted by ID.
+// If f returns false, the iteration stops.
+//
+// If more terms are associated to the same ID, only one of them is visited.
+func (v *Vocabulary) Range(f func(id int, term string) bool) {
+	for _, id := range v.sortedIDs() {
+		if !f(id, v.idToTerm[id]) {
+			return
+		}
+	}
+}
+
+// Validate checks that the IDs of the vocabulary are unique and
+// contiguous, that is, all the IDs from 0 to Size()-1 are associated to
+// exactly one term.
+func (v *Vocabulary) Validate() error {
+	if err := v.CheckDuplicateIDs(); err != nil {
+		return err
+	}
+	for i, id := range v.sortedIDs() {
+		if id != i {
+			return fmt.Errorf("vocabulary IDs are not contiguous: ID %d is missing", i)
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the vocabulary as a JSON object, mapping each term to
+// its ID, with the entries sorted by ID.
+//
+// It returns an error if more terms are associated to the same ID, since
+// only one of them could be written. Gaps in the IDs are allowed.
+func (v *Vocabulary) WriteJSON(w io.Writer) error {
+	if err := v.CheckDuplicateIDs(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString("{")
+	var err error
+	first := true
+	v.Range(func(id int, term string) bool {
+		var key []byte
+		key, err = json.Marshal(term)
+		if err != nil {
+			return false
+		}
+		if !first {
+			bw.WriteString(",")
+		}
+		first = false
+		fmt.Fprintf(bw, "\n  %s: %d", key, id)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if !first {
+		bw.WriteString("\n")
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// WriteTxt writes the vocabulary as text, with one term per line, sorted
+// by ID, as expected by FromTxtFile.
+//
+// It returns an error if the vocabulary does not pass Validate, or a term
+// contains a line break, since such vocabularies cannot be represented in
+// this format.
+func (v *Vocabulary) WriteTxt(w io.Writer) error {
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	var err error
+	v.Range(func(id int, term string) bool {
+		if strings.ContainsAny(term, "\r\n") {
+			err = fmt.Errorf("term %q with ID %d contains a line break", term, id)
+			return false
+		}
+		bw.WriteString(term)
+		bw.WriteByte('\n')
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func (v *Vocabulary) sortedIDs() []int {
+	ids := make([]int, 0, len(v.idToTerm))
+	for id := range v.idToTerm {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// CheckDuplicateIDs returns an error if more terms are associated to the
+// same ID, which can only happen for vocabularies created with FromMap.
+// Unlike Validate, gaps in the IDs are allowed.
+func (v *Vocabulary) CheckDuplicateIDs() error {
+	if len(v.termToID) == len(v.idToTerm) {
+		return nil
+	}
+	terms := make(map[int][]string, len(v.termToID)-len(v.idToTerm))
+	for term, id := range v.termToID {
+		terms[id] = append(terms[id], term)
+	}
+	duplicateID := -1
+	for id, t := range terms {
+		if len(t) > 1 && (duplicateID == -1 || id < duplicateID) {
+			duplicateID = id
+		}
+	}
+	t := terms[duplicateID]
+	sort.Strings(t)
+	return fmt.Errorf("duplicate vocabulary ID %d, associated to terms %q", duplicateID, t)
+}
@@ -5,6 +5,11 @@
 package vocabulary
 
 import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -42,3 +47,308 @@ func TestFromJSONFile(t *testing.T) {
 		}
 	}
 }
+
+func TestFromTxtFile(t *testing.T) {
+	t.Parallel()
+
+	v, err := FromTxtFile("testdata/vocab.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Size() != 3 {
+		t.Errorf("expected Size() == 3, actual %d", v.Size())
+	}
+
+	values := map[string]int{
+		"foo": 0,
+		"bar": 1,
+		"baz": 2,
+	}
+	for term, id := range values {
+		if i, b := v.GetID(term); !b || i != id {
+			t.Errorf(" expected GetID(%#v) == (%d, true), actual (%d, %t)", term, id, i, b)
+		}
+		if s, b := v.GetString(id); !b || s != term {
+			t.Errorf(" expected GetString(%d) == (%#v, true), actual (%#v, %t)", id, term, s, b)
+		}
+	}
+}
+
+func TestFromTxtFileEmpty(t *testing.T) {
+	t.Parallel()
+
+	for _, content := range []string{"", "\n"} {
+		filename := filepath.Join(t.TempDir(), "vocab.txt")
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		v, err := FromTxtFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Size() != 0 {
+			t.Errorf("%q: expected Size() == 0, actual %d", content, v.Size())
+		}
+		if _, ok := v.GetID(""); ok {
+			t.Errorf("%q: unexpected empty term", content)
+		}
+	}
+}
+
+func TestFromMapCopiesTheMap(t *testing.T) {
+	t.Parallel()
+
+	termToID := map[string]int{"foo": 0, "bar": 1}
+	v := FromMap(termToID)
+	termToID["baz"] = 2
+	delete(termToID, "foo")
+
+	assertVocabulary(t, v, map[int]string{0: "foo", 1: "bar"})
+
+	v.AddTerm("qux")
+	if _, ok := termToID["qux"]; ok {
+		t.Error("AddTerm modified the map given to FromMap")
+	}
+}
+
+func TestFromJSONFileDuplicateIDs(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "vocab.json")
+	err := ioutil.WriteFile(filename, []byte(`{"foo": 0, "bar": 1, "baz": 1}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = FromJSONFile(filename)
+	if err == nil || !strings.Contains(err.Error(), `duplicate vocabulary ID 1, associated to terms ["bar" "baz"]`) {
+		t.Errorf("expected duplicate ID error, actual %v", err)
+	}
+}
+
+func TestStrictLoading(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		filename := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+
+	v, err := FromJSONFileStrict("testdata/vocab.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, v.Size(), 3)
+	v, err = FromTxtFileStrict("testdata/vocab.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, v.Size(), 3)
+
+	gapJSON := write("gap.json", `{"foo": 0, "bar": 2}`)
+	if _, err := FromJSONFile(gapJSON); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	_, err = FromJSONFileStrict(gapJSON)
+	if err == nil || !strings.Contains(err.Error(), "vocabulary IDs are not contiguous: ID 1 is missing") {
+		t.Errorf("expected ID gap error, actual %v", err)
+	}
+
+	repeatedTxt := write("repeated.txt", "foo\nbar\nfoo\n")
+	if _, err := FromTxtFile(repeatedTxt); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	_, err = FromTxtFileStrict(repeatedTxt)
+	if err == nil || !strings.Contains(err.Error(), "vocabulary IDs are not contiguous: ID 0 is missing") {
+		t.Errorf("expected ID gap error, actual %v", err)
+	}
+}
+
+func TestVocabularyAddTerm(t *testing.T) {
+	t.Parallel()
+
+	v := FromMap(map[string]int{"foo": 0, "bar": 5})
+	v.AddTerm("baz")
+	v.AddTerm("foo")
+	assertVocabulary(t, v, map[int]string{0: "foo", 5: "bar", 6: "baz"})
+}
+
+func TestVocabularyAddTermWithID(t *testing.T) {
+	t.Parallel()
+
+	v := NewVocabulary()
+	if err := v.AddTermWithID("foo", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.AddTermWithID("foo", 3); err != nil {
+		t.Errorf("expected nil error re-adding the same association, actual %v", err)
+	}
+	if err := v.AddTermWithID("foo", 4); err == nil {
+		t.Error("expected error assigning a new ID to an existing term")
+	}
+	if err := v.AddTermWithID("bar", 3); err == nil {
+		t.Error("expected error assigning an existing ID to a new term")
+	}
+	if err := v.AddTermWithID("bar", -1); err == nil {
+		t.Error("expected error assigning a negative ID")
+	}
+	if err := v.AddTermWithID("bar", 1); err != nil {
+		t.Fatal(err)
+	}
+	v.AddTerm("baz")
+	assertVocabulary(t, v, map[int]string{1: "bar", 3: "foo", 4: "baz"})
+}
+
+func TestVocabularyRemoveTerm(t *testing.T) {
+	t.Parallel()
+
+	v := NewVocabulary()
+	for _, term := range []string{"foo", "bar", "baz"} {
+		v.AddTerm(term)
+	}
+	if v.RemoveTerm("qux") {
+		t.Error("expected RemoveTerm to return false for a missing term")
+	}
+	if !v.RemoveTerm("bar") {
+		t.Error("expected RemoveTerm to return true")
+	}
+	v.AddTerm("qux")
+	assertVocabulary(t, v, map[int]string{0: "foo", 2: "baz", 3: "qux"})
+
+	if !v.RemoveTerm("qux") {
+		t.Error("expected RemoveTerm to return true")
+	}
+	v.AddTerm("quux")
+	assertVocabulary(t, v, map[int]string{0: "foo", 2: "baz", 3: "quux"})
+
+	// With duplicate IDs, the ID stays assigned to the remaining term
+	v = FromMap(map[string]int{"foo": 0, "bar": 1, "baz": 1})
+	term, _ := v.GetString(1)
+	v.RemoveTerm(term)
+	remaining := map[string]string{"bar": "baz", "baz": "bar"}[term]
+	assertVocabulary(t, v, map[int]string{0: "foo", 1: remaining})
+	if err := v.AddTermWithID("qux", 1); err == nil {
+		t.Error("expected AddTermWithID to fail for an ID still assigned")
+	}
+}
+
+func TestVocabularyRange(t *testing.T) {
+	t.Parallel()
+
+	v := FromMap(map[string]int{"c": 2, "a": 0, "d": 10, "b": 1})
+	var ids []int
+	var terms []string
+	v.Range(func(id int, term string) bool {
+		ids = append(ids, id)
+		terms = append(terms, term)
+		return id < 2
+	})
+	assertEqual(t, ids, []int{0, 1, 2})
+	assertEqual(t, terms, []string{"a", "b", "c"})
+}
+
+func TestVocabularyValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		termToID map[string]int
+		err      string
+	}{
+		{map[string]int{}, ""},
+		{map[string]int{"a": 0, "b": 1, "c": 2}, ""},
+		{map[string]int{"a": 0, "b": 2}, "vocabulary IDs are not contiguous: ID 1 is missing"},
+		{map[string]int{"a": 1, "b": 2}, "vocabulary IDs are not contiguous: ID 0 is missing"},
+		{map[string]int{"a": 0, "b": 1, "c": 1}, `duplicate vocabulary ID 1, associated to terms ["b" "c"]`},
+	}
+	for _, tc := range testCases {
+		err := FromMap(tc.termToID).Validate()
+		actual := ""
+		if err != nil {
+			actual = err.Error()
+		}
+		assertEqual(t, actual, tc.err)
+	}
+}
+
+func TestVocabularyWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	v := FromMap(map[string]int{"foo": 0, `"quoted"`: 7, "bar": 1})
+	var buf bytes.Buffer
+	if err := v.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, buf.String(), "{\n  \"foo\": 0,\n  \"bar\": 1,\n  \"\\\"quoted\\\"\": 7\n}\n")
+
+	filename := filepath.Join(t.TempDir(), "vocab.json")
+	if err := ioutil.WriteFile(filename, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := FromJSONFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertVocabulary(t, loaded, map[int]string{0: "foo", 1: "bar", 7: `"quoted"`})
+
+	buf.Reset()
+	if err := NewVocabulary().WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, buf.String(), "{}\n")
+
+	buf.Reset()
+	err = FromMap(map[string]int{"foo": 0, "bar": 1, "baz": 1}).WriteJSON(&buf)
+	if err == nil || err.Error() != `duplicate vocabulary ID 1, associated to terms ["bar" "baz"]` {
+		t.Errorf("expected duplicate ID error, actual %v", err)
+	}
+	assertEqual(t, buf.Len(), 0)
+}
+
+func TestVocabularyWriteTxt(t *testing.T) {
+	t.Parallel()
+
+	v, err := FromJSONFile("testdata/vocab.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := v.WriteTxt(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := ioutil.ReadFile("testdata/vocab.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, buf.String(), string(expected))
+
+	if err := FromMap(map[string]int{"foo": 0, "bar": 2}).WriteTxt(&buf); err == nil {
+		t.Error("expected error writing a vocabulary with ID gaps")
+	}
+	if err := FromMap(map[string]int{"foo\nbar": 0}).WriteTxt(&buf); err == nil {
+		t.Error("expected error writing a term with a line break")
+	}
+}
+
+func assertVocabulary(t *testing.T, v *Vocabulary, expected map[int]string) {
+	t.Helper()
+	actual := make(map[int]string, v.Size())
+	v.Range(func(id int, term string) bool {
+		actual[id] = term
+		if i, ok := v.GetID(term); !ok || i != id {
+			t.Errorf("expected GetID(%#v) == (%d, true), actual (%d, %t)", term, id, i, ok)
+		}
+		return true
+	})
+	assertEqual(t, v.Size(), len(expected))
+	assertEqual(t, actual, expected)
+}
+
+func assertEqual(t *testing.T, actual, expected interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected\n  %#v\nactual\n  %#v", expected, actual)
+	}
+}